@@ -0,0 +1,309 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// Коды завершения подкоманд.
+const (
+	exitOK      = 0
+	exitFailure = 1 // ошибка в схеме или при вычислении
+	exitUsage   = 2 // неверные аргументы
+)
+
+// usageError - ошибка в аргументах командной строки.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// command - подкоманда командной строки.
+type command struct {
+	args  string // описание позиционных аргументов
+	help  string
+	flags func(fs *flag.FlagSet) // объявление флагов, может быть nil
+	run   func(fs *flag.FlagSet, args []string, stdout io.Writer) error
+	nargs int // число позиционных аргументов, -1 - хотя бы один
+}
+
+// reportCommand - подкоманда, которая читает схему из файла
+// и выводит построенный по ней отчет.
+func reportCommand(help string, report func(s *Scheme) (string, error)) command {
+	return command{
+		args:  "FILE",
+		help:  help,
+		nargs: 1,
+		run: func(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+			s, err := loadScheme(args[0])
+			if err != nil {
+				return err
+			}
+			text, err := report(s)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(stdout, text)
+			return err
+		},
+	}
+}
+
+var commands = map[string]command{
+	"table": reportCommand("вывести таблицу истинности схемы", func(s *Scheme) (string, error) {
+		tt, err := s.createTruthTable()
+		if err != nil {
+			return "", err
+		}
+		return tt.String(), nil
+	}),
+	"machine": reportCommand("вывести таблицу переходов и выходов автомата", func(s *Scheme) (string, error) {
+		m, err := s.createMealyMachine()
+		if err != nil {
+			return "", err
+		}
+		return m.String(), nil
+	}),
+	"minimize": reportCommand("минимизировать автомат схемы", func(s *Scheme) (string, error) {
+		m, err := s.createMealyMachine()
+		if err != nil {
+			return "", err
+		}
+		return m.minimize().String(), nil
+	}),
+	"reachable": reportCommand("найти состояния, достижимые из начального", func(s *Scheme) (string, error) {
+		m, err := s.createMealyMachine()
+		if err != nil {
+			return "", err
+		}
+		return m.reachability().String(), nil
+	}),
+	"simplify": reportCommand("вывести упрощенные формулы схемы", func(s *Scheme) (string, error) {
+		return s.simplifiedFormulas(), nil
+	}),
+	"dnf":       reportCommand("найти минимальные ДНФ формул схемы", (*Scheme).dnfReport),
+	"forms":     reportCommand("найти СДНФ, СКНФ и полиномы Жегалкина формул схемы", (*Scheme).canonicalReport),
+	"post":      reportCommand("построить таблицу Поста для формул схемы и проверить их полноту", (*Scheme).postReport),
+	"essential": reportCommand("найти существенные и фиктивные переменные формул схемы", (*Scheme).essentialReport),
+	"run": {
+		args:  "FILE WORD",
+		help:  "вычислить выходное слово по входному",
+		nargs: 2,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("init", "", "начальное состояние - значения задержек подряд, * - все состояния")
+		},
+		run: func(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+			s, err := loadScheme(args[0])
+			if err != nil {
+				return err
+			}
+			inputWord, err := parseWord(args[1], len(s.Inputs))
+			if err != nil {
+				return err
+			}
+			state := fs.Lookup("init").Value.String()
+			if state == "*" {
+				table, err := outputWordsTable(s, inputWord)
+				if err != nil {
+					return err
+				}
+				_, err = io.WriteString(stdout, table)
+				return err
+			}
+			init, err := parseState(s, state)
+			if err != nil {
+				return err
+			}
+			outputWord, err := s.calculateOutputWordFrom(init, inputWord)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(stdout, wordToString(outputWord))
+			return err
+		},
+	},
+	"complete": {
+		args:  "FORMULA...",
+		help:  "построить таблицу Поста для набора элементов и проверить его полноту",
+		nargs: -1,
+		run: func(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+			nodes := make([]boolParser.Node, len(args))
+			for i, arg := range args {
+				node, err := boolParser.ParseString(arg)
+				if err != nil {
+					return err
+				}
+				nodes[i] = node
+			}
+			report, err := postTable(args, nodes)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(stdout, report)
+			return err
+		},
+	},
+	"convert": {
+		args:  "FILE",
+		help:  "записать схему в другом формате: text, json, yaml или dot",
+		nargs: 1,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("to", "", "формат результата, по умолчанию по расширению -o или text")
+			fs.String("o", "", "файл для результата, по умолчанию стандартный вывод")
+		},
+		run: func(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+			format := fs.Lookup("to").Value.String()
+			out := fs.Lookup("o").Value.String()
+			if format == "" && out != "" {
+				format = schemeFormat(out)
+				if strings.HasSuffix(strings.ToLower(out), ".dot") {
+					format = "dot"
+				}
+			}
+			switch format {
+			case "":
+				format = formatText
+			case "dot", formatText, formatJSON, formatYAML:
+			default:
+				return usageError{"unknown format: " + format}
+			}
+
+			s, err := loadScheme(args[0])
+			if err != nil {
+				return err
+			}
+			var data []byte
+			switch format {
+			case "dot":
+				m, err := s.createMealyMachine()
+				if err != nil {
+					return err
+				}
+				data = []byte(m.Dot())
+			default:
+				if data, err = encodeScheme(s, format); err != nil {
+					return err
+				}
+			}
+			if out != "" {
+				return ioutil.WriteFile(out, data, 0644)
+			}
+			_, err = stdout.Write(data)
+			return err
+		},
+	},
+	// ошибки схемы находит уже loadScheme и возвращает с номерами
+	// строк, check выводит только оставшиеся предупреждения
+	"check": {
+		args:  "FILE",
+		help:  "проверить схему, код завершения 1 при ошибках",
+		nargs: 1,
+		run: func(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+			s, err := loadScheme(args[0])
+			if err != nil {
+				return err
+			}
+			for _, d := range s.Validate() {
+				if _, err := fmt.Fprintln(stdout, d.String()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// parseFlags разбирает флаги, стоящие как до, так и после
+// позиционных аргументов, и возвращает позиционные аргументы.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Использование: toi [команда] [флаги] аргументы")
+	fmt.Fprintln(w, "Без аргументов запускается интерактивное меню.")
+	fmt.Fprintln(w, "Команды:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s %s\n    \t%s\n", name, commands[name].args, commands[name].help)
+	}
+	fmt.Fprintln(w, "Формат файла схемы определяется по расширению: .json, .yaml, .yml или текстовый.")
+}
+
+// runCommand выполняет подкоманду и возвращает код завершения.
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, "unknown command:", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("toi "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Использование: toi %s [флаги] %s\n%s\n", args[0], cmd.args, cmd.help)
+		fs.PrintDefaults()
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	positional, err := parseFlags(fs, args[1:])
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if cmd.nargs < 0 && len(positional) == 0 {
+		fmt.Fprintf(stderr, "toi %s: expected arguments\n", args[0])
+		fs.Usage()
+		return exitUsage
+	}
+	if cmd.nargs >= 0 && len(positional) != cmd.nargs {
+		fmt.Fprintf(stderr, "toi %s: expected %d arguments, found %d\n", args[0], cmd.nargs, len(positional))
+		fs.Usage()
+		return exitUsage
+	}
+
+	err = cmd.run(fs, positional, stdout)
+	if _, ok := err.(usageError); ok {
+		fmt.Fprintf(stderr, "toi %s: %s\n", args[0], err)
+		fs.Usage()
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
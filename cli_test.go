@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func runTestCommand(args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := runCommand(args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCommandRun(t *testing.T) {
+	code, stdout, stderr := runTestCommand("run", "testdata/adder.txt", "11 01 10")
+	if code != exitOK || stdout != "000\n" {
+		t.Errorf("expected 000, actual %d %q %q", code, stdout, stderr)
+	}
+	code, stdout, _ = runTestCommand("run", "testdata/unix.txt", "1111", "-init", "1")
+	if code != exitOK || stdout != "0101\n" {
+		t.Errorf("expected 0101, actual %d %q", code, stdout)
+	}
+	if code, _, _ = runTestCommand("run", "testdata/unix.txt", "1111", "-init", "2"); code != exitFailure {
+		t.Errorf("expected exit code %d for invalid state, actual %d", exitFailure, code)
+	}
+}
+
+func TestCommandCheck(t *testing.T) {
+	if code, _, stderr := runTestCommand("check", "testdata/adder.txt"); code != exitOK {
+		t.Errorf("expected valid scheme, actual %d %s", code, stderr)
+	}
+
+	dir, err := ioutil.TempDir("", "toi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bad := filepath.Join(dir, "bad.txt")
+	if err := ioutil.WriteFile(bad, []byte("input: x\noutput: y\ny: x + w\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code, _, stderr := runTestCommand("check", bad)
+	if code != exitFailure || !strings.Contains(stderr, "bad.txt:3:") {
+		t.Errorf("expected error at line 3, actual %d %q", code, stderr)
+	}
+}
+
+func TestCommandConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "toi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expected, err := loadScheme("testdata/adder.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"adder.json", "adder.yaml", "adder.txt"} {
+		out := filepath.Join(dir, name)
+		if code, _, stderr := runTestCommand("convert", "testdata/adder.txt", "-o", out); code != exitOK {
+			t.Errorf("%s: %d %s", name, code, stderr)
+			continue
+		}
+		s, err := loadScheme(out)
+		if err != nil {
+			t.Error(name, err)
+			continue
+		}
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("%s: converted scheme differs:\n%#v\n%#v", name, expected, s)
+		}
+	}
+
+	code, stdout, _ := runTestCommand("convert", "testdata/adder.txt", "--to", "dot")
+	if code != exitOK || !strings.HasPrefix(stdout, "digraph") {
+		t.Errorf("expected dot output, actual %d %q", code, stdout)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	usages := [][]string{
+		{"frob"},
+		{"table"},
+		{"run", "testdata/adder.txt"},
+		{"convert", "testdata/adder.txt", "--to", "xml"},
+		{"table", "-unknown", "testdata/adder.txt"},
+	}
+	for _, args := range usages {
+		if code, _, _ := runTestCommand(args...); code != exitUsage {
+			t.Errorf("%v: expected exit code %d, actual %d", args, exitUsage, code)
+		}
+	}
+	if code, _, _ := runTestCommand("table", "testdata/missing.txt"); code != exitFailure {
+		t.Errorf("expected exit code %d for missing file, actual %d", exitFailure, code)
+	}
+}
+
+func TestCommandComplete(t *testing.T) {
+	sets := map[string][]string{
+		"Система функционально полна":                             {"a | b"},
+		"Система не полна: все функции лежат в классах T0, T1, M": {"a * b", "a + b"},
+	}
+	for expected, gates := range sets {
+		code, stdout, stderr := runTestCommand(append([]string{"complete"}, gates...)...)
+		if code != exitOK || !strings.HasSuffix(stdout, expected+"\n") {
+			t.Errorf("%v: expected %q, actual %d %q %q", gates, expected, code, stdout, stderr)
+		}
+	}
+	if code, _, _ := runTestCommand("complete"); code != exitUsage {
+		t.Errorf("expected exit code %d without formulas, actual %d", exitUsage, code)
+	}
+}
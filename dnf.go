@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// column возвращает значения j-го выхода таблицы истинности.
+func (tt *TruthTable) column(j int) []bool {
+	column := make([]bool, len(tt.values))
+	for i, row := range tt.values {
+		column[i] = row[j]
+	}
+	return column
+}
+
+// minimalDNFs находит минимальные ДНФ выходов и новых значений задержек
+// по столбцам таблицы истинности. Строки с состоянием, недостижимым
+// из начального, считаются неопределенными.
+func (tt *TruthTable) minimalDNFs() ([]*boolParser.DNF, error) {
+	dnfs := make([]*boolParser.DNF, len(tt.outputs))
+	for j := range tt.outputs {
+		d, err := boolParser.MinimizeTruthTable(tt.inputs, tt.column(j), tt.unreachable)
+		if err != nil {
+			return nil, err
+		}
+		dnfs[j] = d
+	}
+	return dnfs, nil
+}
+
+// dnfReport записывает минимальные ДНФ формул схемы вместе с простыми
+// импликантами: "E" - ядровая импликанта, "C" - вошедшая в покрытие.
+func (s Scheme) dnfReport() (string, error) {
+	tt, err := s.createTruthTable()
+	if err != nil {
+		return "", err
+	}
+	dnfs, err := tt.minimalDNFs()
+	if err != nil {
+		return "", err
+	}
+	buffer := ""
+	for j, d := range dnfs {
+		buffer += tt.outputs[j] + ": " + d.String() + "\n"
+		if !d.Exact {
+			buffer += "    Покрытие выбрано жадно и может быть не минимальным\n"
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(d.Report(), "\n"), "\n") {
+			buffer += "    " + line + "\n"
+		}
+	}
+	for _, u := range tt.unreachable {
+		if u {
+			buffer += "Строки с недостижимыми состояниями считаются неопределенными\n"
+			break
+		}
+	}
+	return buffer, nil
+}
+
+// canonicalReport записывает СДНФ, СКНФ и полином Жегалкина выходов
+// и новых значений задержек как функций от входов и задержек.
+func (s Scheme) canonicalReport() (string, error) {
+	tt, err := s.createTruthTable()
+	if err != nil {
+		return "", err
+	}
+	buffer := ""
+	for j, name := range tt.outputs {
+		column := tt.column(j)
+		buffer += name + ":\n"
+		buffer += "    СДНФ: " + boolParser.PerfectDNFOf(tt.inputs, column).Text + "\n"
+		buffer += "    СКНФ: " + boolParser.PerfectCNFOf(tt.inputs, column).Text + "\n"
+		buffer += "    полином Жегалкина: " + boolParser.ZhegalkinOf(tt.inputs, column).Text + "\n"
+	}
+	return buffer, nil
+}
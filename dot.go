@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dot выводит диаграмму состояний автомата на языке Graphviz:
+// вершина — состояние, дуга подписана "вход/выход",
+// начальное состояние выделено и отмечено входящей стрелкой,
+// недостижимые из него состояния и их дуги нарисованы пунктиром.
+func (m *MealyMachine) Dot() string {
+	reachable := m.reachability().Reachable
+
+	var buffer bytes.Buffer
+	buffer.WriteString("digraph mealy {\n")
+	buffer.WriteString("\trankdir=LR;\n")
+	buffer.WriteString("\tnode [shape=circle];\n")
+	buffer.WriteString("\t__start [shape=point];\n")
+
+	for q := 0; q < m.StatesCount(); q++ {
+		attrs := ""
+		if q == m.Initial {
+			attrs = " [penwidth=2]"
+		} else if !reachable[q] {
+			attrs = " [style=dashed, color=gray]"
+		}
+		fmt.Fprintf(&buffer, "\t%s%s;\n", strconv.Quote(m.StateName(q)), attrs)
+	}
+	fmt.Fprintf(&buffer, "\t__start -> %s;\n", strconv.Quote(m.StateName(m.Initial)))
+
+	for q := 0; q < m.StatesCount(); q++ {
+		// буквы, переводящие в одно и то же состояние, подписываются на одной дуге
+		labels := map[int][]string{}
+		targets := []int{}
+		for a, next := range m.Next[q] {
+			if _, ok := labels[next]; !ok {
+				targets = append(targets, next)
+			}
+			labels[next] = append(labels[next], m.LetterName(a)+"/"+m.OutputName(m.Output[q][a]))
+		}
+		style := ""
+		if !reachable[q] {
+			style = ", style=dashed, color=gray"
+		}
+		for _, next := range targets {
+			fmt.Fprintf(&buffer, "\t%s -> %s [label=%s%s];\n",
+				strconv.Quote(m.StateName(q)), strconv.Quote(m.StateName(next)),
+				strconv.Quote(strings.Join(labels[next], ", ")), style)
+		}
+	}
+	buffer.WriteString("}\n")
+	return buffer.String()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDot(t *testing.T) {
+	s, err := createSchemeFromFile(filepath.Join("testdata", "unix.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.createMealyMachine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph mealy {
+	rankdir=LR;
+	node [shape=circle];
+	__start [shape=point];
+	"0" [penwidth=2];
+	"1";
+	__start -> "0";
+	"0" -> "0" [label="0/0"];
+	"0" -> "1" [label="1/1"];
+	"1" -> "1" [label="0/1"];
+	"1" -> "0" [label="1/0"];
+}
+`
+	if actual := m.Dot(); actual != expected {
+		t.Errorf("expected\n%s\nactual\n%s", expected, actual)
+	}
+}
+
+func TestDotUnreachableAndMergedEdges(t *testing.T) {
+	// из 0 в 1 не попасть, обе буквы оставляют автомат в том же состоянии
+	s, err := parseScheme("scheme.txt", strings.NewReader("input: x\noutput: y\nmemory: z\ny: z * x\nz: z\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.createMealyMachine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := m.Dot()
+	lines := []string{
+		"\t\"0\" [penwidth=2];\n",
+		"\t\"1\" [style=dashed, color=gray];\n",
+		"\t\"0\" -> \"0\" [label=\"0/0, 1/0\"];\n",
+		"\t\"1\" -> \"1\" [label=\"0/0, 1/1\", style=dashed, color=gray];\n",
+	}
+	for _, line := range lines {
+		if !strings.Contains(dot, line) {
+			t.Errorf("expected line %q in\n%s", line, dot)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// maxInfluenceVars - наибольшее число входов и задержек, при котором
+// Validate строит таблицу истинности, чтобы найти влияющие задержки.
+const maxInfluenceVars = 20
+
+// influence находит входы и задержки, которые влияют на выходы схемы
+// в какой-нибудь момент времени: переменная влияет, если она существенна
+// для функции какого-нибудь выхода или для функции следующего значения
+// влияющей задержки. Функции берутся от входов и текущих значений
+// задержек, выходы, на которые ссылаются формулы, уже подставлены.
+func (s Scheme) influence() (map[string]bool, error) {
+	delays := s.delays()
+	vars := append(append([]string{}, s.Inputs...), delays...)
+	values, err := s.calculateAll(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	influencing := map[string]bool{}
+	queue := append([]string{}, s.Outputs...)
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		essential, _ := boolParser.EssentialOf(vars, values[name])
+		for _, v := range essential {
+			influencing[v] = true
+			if !s.isInput(v) {
+				queue = append(queue, v)
+			}
+		}
+	}
+	return influencing, nil
+}
+
+// joinNames записывает имена через запятую или "-", если их нет.
+func joinNames(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+// essentialReport записывает существенные и фиктивные переменные
+// каждой формулы и переменные, от которых зависят выходы схемы.
+func (s Scheme) essentialReport() (string, error) {
+	buffer := ""
+	for _, v := range s.formulas() {
+		essential, fictitious, err := boolParser.Essential(s.Memory[v])
+		if err != nil {
+			return "", err
+		}
+		buffer += v + ": " + s.source(v) + "\n"
+		buffer += "    существенные: " + joinNames(essential) + "\n"
+		buffer += "    фиктивные: " + joinNames(fictitious) + "\n"
+	}
+
+	influencing, err := s.influence()
+	if err != nil {
+		return "", err
+	}
+	affect, never := []string{}, []string{}
+	for _, v := range append(append([]string{}, s.Inputs...), s.delays()...) {
+		if influencing[v] {
+			affect = append(affect, v)
+		} else {
+			never = append(never, v)
+		}
+	}
+	buffer += "На выходы схемы влияют: " + joinNames(affect) + "\n"
+	buffer += "Не влияют на выходы: " + joinNames(never) + "\n"
+	return buffer, nil
+}
@@ -0,0 +1,126 @@
+package boolParser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BitNamespace maps variable names to lane masks: bit i of a mask is the
+// value of the variable in the i-th of 64 assignments evaluated at once.
+type BitNamespace map[string]uint64
+
+// Lanes is the number of assignments in a lane mask.
+const Lanes = 64
+
+// laneMasks[k] is the mask of bit k of lane numbers 0..63.
+var laneMasks = [6]uint64{
+	0xAAAAAAAAAAAAAAAA,
+	0xCCCCCCCCCCCCCCCC,
+	0xF0F0F0F0F0F0F0F0,
+	0xFF00FF00FF00FF00,
+	0xFFFF0000FFFF0000,
+	0xFFFFFFFF00000000,
+}
+
+// Assignments sets masks of vars in ns for rows block*64 .. block*64+63 of
+// the truth table over vars, where vars[0] is the most significant bit of
+// the row number. Lanes past the last row repeat earlier rows.
+func Assignments(ns BitNamespace, vars []string, block int) {
+	for i, name := range vars {
+		bit := uint(len(vars) - 1 - i)
+		switch {
+		case bit < 6:
+			ns[name] = laneMasks[bit]
+		case (block>>(bit-6))&1 == 1:
+			ns[name] = ^uint64(0)
+		default:
+			ns[name] = 0
+		}
+	}
+}
+
+// CalculateBits evaluates node on 64 assignments at once, one per lane
+// of the masks in ns.
+func CalculateBits(node Node, ns BitNamespace) (uint64, error) {
+	switch n := node.(type) {
+	case Identifier:
+		val, ok := ns[n.Name]
+		if !ok {
+			return 0, errors.New("Var '" + n.Name + "' not found")
+		}
+		return val, nil
+	case Const:
+		if n.Value == "1" {
+			return ^uint64(0), nil
+		}
+		return 0, nil
+	case NegationNode:
+		return calculateNegationBits(n.expr, ns)
+	case *NegationNode:
+		return calculateNegationBits(n.expr, ns)
+	case BinaryNode:
+		return calculateBinaryBits(n, ns)
+	}
+	return 0, fmt.Errorf("Cannot calculate node %T", node)
+}
+
+func calculateNegationBits(expr Node, ns BitNamespace) (uint64, error) {
+	val, err := CalculateBits(expr, ns)
+	if err != nil {
+		return 0, err
+	}
+	return ^val, nil
+}
+
+func calculateBinaryBits(node BinaryNode, ns BitNamespace) (uint64, error) {
+	l, err := CalculateBits(node.LeftExpression(), ns)
+	if err != nil {
+		return 0, err
+	}
+	r, err := CalculateBits(node.RightExpression(), ns)
+	if err != nil {
+		return 0, err
+	}
+	switch node.(type) {
+	case *UnionNode:
+		return l | r, nil
+	case *IntersectionNode:
+		return l & r, nil
+	case *NandNode:
+		return ^(l & r), nil
+	case *NorNode:
+		return ^(l | r), nil
+	case *DifferenceNode:
+		return l &^ r, nil
+	case *XorNode:
+		return l ^ r, nil
+	case *EquivalenceNode:
+		return ^(l ^ r), nil
+	case *ImplicationNode:
+		return ^l | r, nil
+	}
+	return 0, fmt.Errorf("Cannot calculate node %T", node)
+}
+
+// TruthVector returns values of node on all 2^len(vars) assignments of
+// vars in truth table order, vars[0] being the most significant bit of
+// the row number.
+func TruthVector(node Node, vars []string) ([]bool, error) {
+	if len(vars) > 30 {
+		return nil, errors.New("Too many variables for a truth table")
+	}
+	rows := 1 << uint(len(vars))
+	values := make([]bool, rows)
+	ns := make(BitNamespace, len(vars))
+	for block := 0; block*Lanes < rows; block++ {
+		Assignments(ns, vars, block)
+		mask, err := CalculateBits(node, ns)
+		if err != nil {
+			return nil, err
+		}
+		for lane := 0; lane < Lanes && block*Lanes+lane < rows; lane++ {
+			values[block*Lanes+lane] = mask&(1<<uint(lane)) != 0
+		}
+	}
+	return values, nil
+}
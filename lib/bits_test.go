@@ -0,0 +1,86 @@
+package boolParser
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testsBits = []string{
+	"0",
+	"1",
+	"a",
+	"!a * b",
+	"a + b - c",
+	"a | b ↓ !c",
+	"a ^ b ~ c -> d",
+	"(a * b + c * d) ^ (e + f * g) -> !h",
+}
+
+func TestCalculateBitsMatchesCalculate(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for _, str := range testsBits {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		values, err := TruthVector(node, vars)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		for row, value := range values {
+			ns := Namespace{}
+			for i, name := range vars {
+				ns[name] = row&(1<<uint(len(vars)-1-i)) != 0
+			}
+			expected, err := node.Calculate(ns)
+			if err != nil {
+				t.Error(str, err.Error())
+				break
+			}
+			if value != expected {
+				t.Errorf("%s: row %d expected %v, actual %v", str, row, expected, value)
+				break
+			}
+		}
+	}
+}
+
+func TestTruthVectorSmall(t *testing.T) {
+	t.Parallel()
+	node, err := ParseString("a -> b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := TruthVector(node, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := fmt.Sprint(values); actual != "[true true false true]" {
+		t.Error("expected [true true false true], actual", actual)
+	}
+}
+
+func TestCalculateBitsUndefinedVar(t *testing.T) {
+	t.Parallel()
+	node, err := ParseString("a + b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TruthVector(node, []string{"a"}); err == nil {
+		t.Error("expected error for undefined var b")
+	}
+}
+
+func BenchmarkTruthVector(b *testing.B) {
+	node, err := ParseString("(a * b + c * d) ^ (e + f * g) -> !h")
+	if err != nil {
+		b.Fatal(err)
+	}
+	vars := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+	for i := 0; i < b.N; i++ {
+		TruthVector(node, vars)
+	}
+}
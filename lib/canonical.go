@@ -0,0 +1,127 @@
+package boolParser
+
+// NormalForm is a normal form of a function as a node and as text.
+type NormalForm struct {
+	Node Node
+	Text string // Format of Node
+}
+
+func normalForm(node Node) NormalForm {
+	return NormalForm{Node: node, Text: Format(node)}
+}
+
+// PerfectDNF returns the perfect disjunctive normal form of node over
+// vars: the union of full products for the true rows of its truth table.
+func PerfectDNF(node Node, vars []string) (NormalForm, error) {
+	values, err := TruthVector(node, vars)
+	if err != nil {
+		return NormalForm{}, err
+	}
+	return PerfectDNFOf(vars, values), nil
+}
+
+// PerfectDNFOf returns the perfect DNF of the function with the given
+// column of the truth table over vars.
+func PerfectDNFOf(vars []string, values []bool) NormalForm {
+	products := []Implicant{}
+	for row, value := range values {
+		if value {
+			products = append(products, Implicant{Value: row})
+		}
+	}
+	return normalForm(sumOfProducts(products, vars))
+}
+
+// PerfectCNF returns the perfect conjunctive normal form of node over
+// vars: the intersection of full sums for the false rows of its truth
+// table.
+func PerfectCNF(node Node, vars []string) (NormalForm, error) {
+	values, err := TruthVector(node, vars)
+	if err != nil {
+		return NormalForm{}, err
+	}
+	return PerfectCNFOf(vars, values), nil
+}
+
+// PerfectCNFOf returns the perfect CNF of the function with the given
+// column of the truth table over vars.
+func PerfectCNFOf(vars []string, values []bool) NormalForm {
+	var node Node
+	for row, value := range values {
+		if value {
+			continue
+		}
+		// the sum is false on row only: a variable is negated
+		// if its value in the row is 1
+		var clause Node
+		for i, name := range vars {
+			var literal Node = Identifier{Name: name}
+			if row&(1<<uint(len(vars)-1-i)) != 0 {
+				literal = NegationNode{expr: literal}
+			}
+			if clause == nil {
+				clause = literal
+			} else {
+				clause = &UnionNode{BinaryNodeStruct{LExpr: clause, RExpr: literal}}
+			}
+		}
+		if clause == nil {
+			clause = Const{Value: "0"}
+		}
+		if node == nil {
+			node = clause
+		} else {
+			node = &IntersectionNode{BinaryNodeStruct{LExpr: node, RExpr: clause}}
+		}
+	}
+	if node == nil {
+		node = Const{Value: "1"}
+	}
+	return normalForm(node)
+}
+
+// ZhegalkinCoefficients returns coefficients of the Zhegalkin polynomial
+// of the function with the given column of the truth table: coefficient
+// m is set if the product of variables with set bits of m is a term.
+func ZhegalkinCoefficients(values []bool) []bool {
+	coefficients := append([]bool{}, values...)
+	for bit := 1; bit < len(coefficients); bit <<= 1 {
+		for m := range coefficients {
+			if m&bit != 0 {
+				coefficients[m] = coefficients[m] != coefficients[m^bit]
+			}
+		}
+	}
+	return coefficients
+}
+
+// Zhegalkin returns the Zhegalkin polynomial (algebraic normal form) of
+// node over vars: XOR of products of variables and, possibly, 1.
+func Zhegalkin(node Node, vars []string) (NormalForm, error) {
+	values, err := TruthVector(node, vars)
+	if err != nil {
+		return NormalForm{}, err
+	}
+	return ZhegalkinOf(vars, values), nil
+}
+
+// ZhegalkinOf returns the Zhegalkin polynomial of the function with
+// the given column of the truth table over vars.
+func ZhegalkinOf(vars []string, values []bool) NormalForm {
+	all := len(values) - 1
+	terms := []Implicant{}
+	for m, c := range ZhegalkinCoefficients(values) {
+		if c {
+			terms = append(terms, Implicant{Value: m, Free: all &^ m})
+		}
+	}
+	sortImplicants(terms, len(vars))
+	if len(terms) == 0 {
+		return normalForm(Const{Value: "0"})
+	}
+	node := terms[0].Node(vars)
+	for _, term := range terms[1:] {
+		node = &XorNode{BinaryNodeStruct{LExpr: node, RExpr: term.Node(vars)}}
+	}
+	return normalForm(node)
+}
@@ -0,0 +1,82 @@
+package boolParser
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var testsCanonical = []struct {
+	str, dnf, cnf, anf string
+}{
+	{"a -> b", "!a * !b + !a * b + a * b", "!a + b", "a * b ^ a ^ 1"},
+	{"a + b", "!a * b + a * !b + a * b", "a + b", "a * b ^ a ^ b"},
+	{"a ~ b", "!a * !b + a * b", "(a + !b) * (!a + b)", "a ^ b ^ 1"},
+	{"a * !a", "0", "(a + b) * (a + !b) * (!a + b) * (!a + !b)", "0"},
+	{"a + 1", "!a * !b + !a * b + a * !b + a * b", "1", "1"},
+}
+
+func TestCanonicalForms(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b"}
+	for _, test := range testsCanonical {
+		node, err := ParseString(test.str)
+		if err != nil {
+			t.Error(test.str, err.Error())
+			continue
+		}
+		forms := []struct {
+			name     string
+			convert  func(Node, []string) (NormalForm, error)
+			expected string
+		}{
+			{"perfect DNF", PerfectDNF, test.dnf},
+			{"perfect CNF", PerfectCNF, test.cnf},
+			{"Zhegalkin polynomial", Zhegalkin, test.anf},
+		}
+		for _, form := range forms {
+			result, err := form.convert(node, vars)
+			if err != nil {
+				t.Error(test.str, form.name, err.Error())
+				continue
+			}
+			if result.Text != form.expected {
+				t.Errorf("%s: expected %s %s, actual %s", test.str, form.name, form.expected, result.Text)
+			}
+			if actual := Format(result.Node); actual != result.Text {
+				t.Errorf("%s: %s text %s differs from node %s", test.str, form.name, result.Text, actual)
+			}
+		}
+	}
+}
+
+func TestCanonicalFormsEquivalent(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d"}
+	rnd := rand.New(rand.NewSource(4))
+	for i := 0; i < 500; i++ {
+		node := randomNode(rnd, vars, 4)
+		expected, err := TruthVector(node, vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, convert := range []func(Node, []string) (NormalForm, error){PerfectDNF, PerfectCNF, Zhegalkin} {
+			form, err := convert(node, vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the textual form parses back into the same function
+			parsed, err := ParseString(form.Text)
+			if err != nil {
+				t.Fatal(form.Text, err)
+			}
+			actual, err := TruthVector(parsed, vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("%s: %s is not equivalent", Format(node), form.Text)
+			}
+		}
+	}
+}
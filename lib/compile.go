@@ -0,0 +1,71 @@
+package boolParser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Program is an expression compiled for evaluation over a slice of
+// variable values, see Compile.
+type Program func(env []bool) bool
+
+// Compile resolves identifiers of node to indexes of env given by slots
+// once and returns a closure tree evaluating node without map lookups.
+func Compile(node Node, slots map[string]int) (Program, error) {
+	switch n := node.(type) {
+	case Identifier:
+		slot, ok := slots[n.Name]
+		if !ok {
+			return nil, errors.New("Var '" + n.Name + "' not found")
+		}
+		return func(env []bool) bool { return env[slot] }, nil
+	case Const:
+		value := n.Value == "1"
+		return func(env []bool) bool { return value }, nil
+	case NegationNode:
+		return compileNegation(n.expr, slots)
+	case *NegationNode:
+		return compileNegation(n.expr, slots)
+	case BinaryNode:
+		return compileBinary(n, slots)
+	}
+	return nil, fmt.Errorf("Cannot compile node %T", node)
+}
+
+func compileNegation(expr Node, slots map[string]int) (Program, error) {
+	e, err := Compile(expr, slots)
+	if err != nil {
+		return nil, err
+	}
+	return func(env []bool) bool { return !e(env) }, nil
+}
+
+func compileBinary(node BinaryNode, slots map[string]int) (Program, error) {
+	l, err := Compile(node.LeftExpression(), slots)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Compile(node.RightExpression(), slots)
+	if err != nil {
+		return nil, err
+	}
+	switch node.(type) {
+	case *UnionNode:
+		return func(env []bool) bool { return l(env) || r(env) }, nil
+	case *IntersectionNode:
+		return func(env []bool) bool { return l(env) && r(env) }, nil
+	case *NandNode:
+		return func(env []bool) bool { return !(l(env) && r(env)) }, nil
+	case *NorNode:
+		return func(env []bool) bool { return !(l(env) || r(env)) }, nil
+	case *DifferenceNode:
+		return func(env []bool) bool { return l(env) && !r(env) }, nil
+	case *XorNode:
+		return func(env []bool) bool { return l(env) != r(env) }, nil
+	case *EquivalenceNode:
+		return func(env []bool) bool { return l(env) == r(env) }, nil
+	case *ImplicationNode:
+		return func(env []bool) bool { return !l(env) || r(env) }, nil
+	}
+	return nil, fmt.Errorf("Cannot compile node %T", node)
+}
@@ -0,0 +1,68 @@
+package boolParser
+
+import "testing"
+
+func TestCompileMatchesCalculate(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	slots := map[string]int{}
+	for i, name := range vars {
+		slots[name] = i
+	}
+	for _, str := range testsBits {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		program, err := Compile(node, slots)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		env := make([]bool, len(vars))
+		for row := 0; row < 1<<uint(len(vars)); row++ {
+			ns := Namespace{}
+			for i, name := range vars {
+				env[i] = row&(1<<uint(len(vars)-1-i)) != 0
+				ns[name] = env[i]
+			}
+			expected, err := node.Calculate(ns)
+			if err != nil {
+				t.Error(str, err.Error())
+				break
+			}
+			if actual := program(env); actual != expected {
+				t.Errorf("%s: row %d expected %v, actual %v", str, row, expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestCompileUndefinedVar(t *testing.T) {
+	t.Parallel()
+	node, err := ParseString("a + !(b * c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Compile(node, map[string]int{"a": 0, "b": 1}); err == nil {
+		t.Error("expected error for undefined var c")
+	}
+}
+
+func BenchmarkCompiled(b *testing.B) {
+	node, err := ParseString("(a * b + c * d) ^ (e + f * g) -> !h")
+	if err != nil {
+		b.Fatal(err)
+	}
+	program, err := Compile(node, map[string]int{"a": 0, "b": 1, "c": 2, "d": 3, "e": 4, "f": 5, "g": 6, "h": 7})
+	if err != nil {
+		b.Fatal(err)
+	}
+	env := make([]bool, 8)
+	for i := 0; i < b.N; i++ {
+		env[i%8] = !env[i%8]
+		program(env)
+	}
+}
@@ -0,0 +1,38 @@
+package boolParser
+
+// EssentialOf splits vars into essential and fictitious variables of the
+// function with the given column of the truth table over vars, vars[0]
+// being the most significant bit of the row number. A variable is
+// essential if changing only its value changes the value of the function
+// on some row.
+func EssentialOf(vars []string, values []bool) (essential, fictitious []string) {
+	essential, fictitious = []string{}, []string{}
+	for i, name := range vars {
+		bit := 1 << uint(len(vars)-1-i)
+		found := false
+		for row := range values {
+			if row&bit == 0 && values[row] != values[row|bit] {
+				found = true
+				break
+			}
+		}
+		if found {
+			essential = append(essential, name)
+		} else {
+			fictitious = append(fictitious, name)
+		}
+	}
+	return essential, fictitious
+}
+
+// Essential splits variables of node into essential and fictitious ones,
+// e.g. for x + x * z x is essential and z is fictitious.
+func Essential(node Node) (essential, fictitious []string, err error) {
+	vars := Variables(node)
+	values, err := TruthVector(node, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	essential, fictitious = EssentialOf(vars, values)
+	return essential, fictitious, nil
+}
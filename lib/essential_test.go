@@ -0,0 +1,37 @@
+package boolParser
+
+import (
+	"strings"
+	"testing"
+)
+
+var testsEssential = map[string][2]string{
+	"1":                  {"", ""},
+	"x":                  {"x", ""},
+	"x + x * z":          {"x", "z"},
+	"a ^ b ^ a":          {"b", "a"},
+	"(a + !a) * b + c*0": {"b", "a c"},
+	"a * b + a * !b + c": {"a c", "b"},
+	"a -> (b -> a)":      {"", "a b"},
+}
+
+func TestEssential(t *testing.T) {
+	t.Parallel()
+	for str, expected := range testsEssential {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		essential, fictitious, err := Essential(node)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		actual := [2]string{strings.Join(essential, " "), strings.Join(fictitious, " ")}
+		if actual != expected {
+			t.Errorf("%s: expected essential %q and fictitious %q, actual %q and %q",
+				str, expected[0], expected[1], actual[0], actual[1])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package boolParser
+
+// precedence returns the priority of the operator of node, see grammar
+// in README: the greater, the tighter it binds.
+func precedence(node Node) int {
+	switch node.(type) {
+	case *ImplicationNode:
+		return 1
+	case *EquivalenceNode:
+		return 2
+	case *XorNode:
+		return 3
+	case *UnionNode, *DifferenceNode, *NorNode:
+		return 4
+	case *IntersectionNode, *NandNode:
+		return 5
+	}
+	return 6
+}
+
+var operators = map[TokenType]string{
+	tokUnion:        " + ",
+	tokIntersection: " * ",
+	tokNand:         " | ",
+	tokNor:          " ↓ ",
+	tokDifference:   " - ",
+	tokXor:          " ^ ",
+	tokEquivalence:  " ~ ",
+	tokImplication:  " -> ",
+}
+
+func operator(node BinaryNode) string {
+	switch node.(type) {
+	case *UnionNode:
+		return operators[tokUnion]
+	case *IntersectionNode:
+		return operators[tokIntersection]
+	case *NandNode:
+		return operators[tokNand]
+	case *NorNode:
+		return operators[tokNor]
+	case *DifferenceNode:
+		return operators[tokDifference]
+	case *XorNode:
+		return operators[tokXor]
+	case *EquivalenceNode:
+		return operators[tokEquivalence]
+	}
+	return operators[tokImplication]
+}
+
+// Format writes node with the least parentheses needed to parse it back
+// into the same tree, unlike String which parenthesises every operation.
+func Format(node Node) string {
+	switch n := node.(type) {
+	case NegationNode:
+		return "!" + formatOperand(n.expr, 6)
+	case *NegationNode:
+		return "!" + formatOperand(n.expr, 6)
+	case BinaryNode:
+		p := precedence(node)
+		// implication is right-associative, the other operations are left-associative
+		lp, rp := p, p+1
+		if _, ok := node.(*ImplicationNode); ok {
+			lp, rp = p+1, p
+		}
+		return formatOperand(n.LeftExpression(), lp) + operator(n) + formatOperand(n.RightExpression(), rp)
+	}
+	return node.String()
+}
+
+// formatOperand formats node in parentheses if its operator binds looser
+// than min.
+func formatOperand(node Node, min int) string {
+	if precedence(node) < min {
+		return "(" + Format(node) + ")"
+	}
+	return Format(node)
+}
@@ -1,7 +1,10 @@
 package boolParser
 
 import (
+	"bufio"
 	"io"
+	"strings"
+	"unicode/utf8"
 )
 
 type TokenType int
@@ -14,7 +17,13 @@ const (
 	// operations
 	// binary
 	tokUnion        // +
+	tokDifference   // '-' or '\'
 	tokIntersection // *
+	tokNand         // '|'
+	tokNor          // '↓'
+	tokXor          // '^' or '⊕'
+	tokEquivalence  // '~' or "=="
+	tokImplication  // "->"
 
 	// unary
 	tokNegation // '!'
@@ -40,8 +49,20 @@ func (t TokenType) String() string {
 		return "Identifier"
 	case tokUnion:
 		return "Union"
+	case tokDifference:
+		return "Difference"
 	case tokIntersection:
 		return "Intersection"
+	case tokNand:
+		return "Nand"
+	case tokNor:
+		return "Nor"
+	case tokXor:
+		return "Xor"
+	case tokEquivalence:
+		return "Equivalence"
+	case tokImplication:
+		return "Implication"
 	case tokNegation:
 		return "Negation"
 	case tokOpeningBraces:
@@ -71,13 +92,35 @@ type Token struct {
 
 // some reusable constant tokens
 var singleCharTokens map[byte]TokenType = map[byte]TokenType{
-	'+': tokUnion,
-	'*': tokIntersection,
-	'!': tokNegation,
-	'(': tokOpeningParenthesis,
-	')': tokClosingParenthesis,
-	'[': tokOpeningBraces,
-	']': tokClosingBraces,
+	'+':  tokUnion,
+	'\\': tokDifference,
+	'*':  tokIntersection,
+	'|':  tokNand,
+	'^':  tokXor,
+	'~':  tokEquivalence,
+	'!':  tokNegation,
+	'(':  tokOpeningParenthesis,
+	')':  tokClosingParenthesis,
+	'[':  tokOpeningBraces,
+	']':  tokClosingBraces,
+}
+
+// operators which are longer than one byte or are a prefix of a longer one
+var multiCharTokens map[string]TokenType = map[string]TokenType{
+	"-":  tokDifference,
+	"->": tokImplication,
+	"==": tokEquivalence,
+	"⊕":  tokXor,
+	"↓":  tokNor,
+}
+
+func isOperatorPrefix(s string) bool {
+	for op := range multiCharTokens {
+		if strings.HasPrefix(op, s) {
+			return true
+		}
+	}
+	return false
 }
 
 func isBinDigit(c byte) bool {
@@ -92,71 +135,136 @@ func isAlphaDig(c byte) bool {
 	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
 }
 
-func readIdentifier(p []byte, n int, i int, r io.Reader) (string, int, int, error) {
-	startIndex := i
-	str := ""
-	i++
+// Scanner splits the input into tokens on demand, so nothing is left
+// running when the parser stops early. It implements TokenStream.
+type Scanner struct {
+	r      *bufio.Reader
+	err    error       // first error of r, io.EOF included
+	offset TokenOffset // offset of the next unread byte
+	stack  []Token     // tokens which are peeked or put back
+	end    *Token      // tokEOF or tokError, returned forever once reached
+}
+
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+// Peek returns the next token without consuming it.
+func (s *Scanner) Peek() Token {
+	if len(s.stack) == 0 {
+		s.stack = append(s.stack, s.scan())
+	}
+	return s.stack[len(s.stack)-1]
+}
+
+// Next consumes the next token. After tokEOF or tokError
+// the same token is returned on every call.
+func (s *Scanner) Next() Token {
+	token := s.Peek()
+	s.stack = s.stack[:len(s.stack)-1]
+	return token
+}
+
+func (s *Scanner) readByte() (byte, bool) {
+	if s.err != nil {
+		return 0, false
+	}
+	var char byte
+	char, s.err = s.r.ReadByte()
+	if s.err != nil {
+		return 0, false
+	}
+	s.offset++
+	return char, true
+}
+
+func (s *Scanner) unreadByte() {
+	s.r.UnreadByte()
+	s.offset--
+}
+
+func (s *Scanner) stop(token Token) Token {
+	s.end = &token
+	return token
+}
+
+func (s *Scanner) scan() Token {
+	if s.end != nil {
+		return *s.end
+	}
 
 	for {
-		for ; i < n; i++ {
-			if !isAlphaDig(p[i]) {
-				str += string(p[startIndex:i])
-				return str, n, i - 1, nil
+		offset := s.offset
+		char, ok := s.readByte()
+		if !ok {
+			if s.err == io.EOF {
+				return s.stop(Token{_type: tokEOF, offset: offset})
 			}
+			return s.stop(Token{_type: tokError, offset: offset, value: s.err.Error()})
 		}
-		str += string(p[startIndex:n])
-		i = 0
-		startIndex = 0
-
-		var err error
-		n, err = r.Read(p)
-		if err != nil {
-			return str, n, i, err
+
+		switch {
+		case char <= ' ':
+			continue
+		case isOperatorPrefix(string([]byte{char})):
+			op := s.readOperator(char)
+			if _type, ok := multiCharTokens[op]; ok {
+				return Token{_type: _type, offset: offset, value: op}
+			}
+			return s.stop(Token{_type: tokError, offset: offset, value: s.completeRune(op)})
+		case singleCharTokens[char] != tokUndefined:
+			return Token{_type: singleCharTokens[char], offset: offset, value: string(char)}
+		case isBinDigit(char):
+			return Token{_type: tokConst, offset: offset, value: string(char)}
+		case isAlpha(char):
+			return Token{_type: tokIdent, offset: offset, value: s.readIdentifier(char)}
+		default:
+			return s.stop(Token{_type: tokError, offset: offset, value: s.completeRune(string([]byte{char}))})
 		}
 	}
 }
 
-func Lexer(r io.Reader, out chan<- Token) {
-	var err error
-	var p []byte = make([]byte, 256)
-	offset := TokenOffset(0)
-
+func (s *Scanner) readIdentifier(first byte) string {
+	ident := []byte{first}
 	for {
-		var n int
-		n, err = r.Read(p)
-		if err != nil {
-			break
+		char, ok := s.readByte()
+		if !ok {
+			return string(ident)
+		}
+		if !isAlphaDig(char) {
+			s.unreadByte()
+			return string(ident)
 		}
+		ident = append(ident, char)
+	}
+}
 
-		for i := 0; i < n; i++ {
-			char := p[i]
-
-			switch {
-			case char <= ' ':
-				offset++
-			case singleCharTokens[char] != tokUndefined:
-				out <- Token{_type: singleCharTokens[char], offset: offset, value: string(char)}
-				offset++
-			case isBinDigit(char):
-				out <- Token{_type: tokConst, offset: offset, value: string(char)}
-				offset++
-			case isAlpha(char):
-				var ident string
-				ident, n, i, err = readIdentifier(p, n, i, r)
-				out <- Token{_type: tokIdent, offset: offset, value: ident}
-				offset += TokenOffset(len(ident))
-
-				if err != nil {
-					break
-				}
-			default:
-				break
-			}
+// readOperator reads the longest sequence of bytes starting with first
+// that is still a prefix of some operator from multiCharTokens.
+func (s *Scanner) readOperator(first byte) string {
+	op := []byte{first}
+	for {
+		char, ok := s.readByte()
+		if !ok {
+			return string(op)
+		}
+		if !isOperatorPrefix(string(append(op, char))) {
+			s.unreadByte()
+			return string(op)
 		}
+		op = append(op, char)
 	}
-	if err == io.EOF {
-		out <- Token{_type: tokEOF, offset: offset}
-		return
+}
+
+// completeRune reads the rest of a multibyte character,
+// so error messages show it as a whole.
+func (s *Scanner) completeRune(str string) string {
+	for len(str) < utf8.UTFMax && !utf8.FullRuneInString(str) {
+		char, ok := s.readByte()
+		if !ok {
+			break
+		}
+		str += string([]byte{char})
 	}
-	out <- Token{_type: tokError, offset: offset, value: err.Error()}
+	return str
 }
@@ -1,7 +1,12 @@
 package boolParser
 
-import "testing"
-import "strings"
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
 
 func TestsIsbinDigit(t *testing.T) {
 	t.Parallel()
@@ -93,6 +98,46 @@ var testsLexer = map[string]([]Token){
 		Token{_type: tokClosingParenthesis, value: ")"},
 		Token{_type: tokEOF},
 	},
+	"a - b \\ c": {
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokDifference, value: "-"},
+		Token{_type: tokIdent, value: "b"},
+		Token{_type: tokDifference, value: "\\"},
+		Token{_type: tokIdent, value: "c"},
+		Token{_type: tokEOF},
+	},
+	"a^b ⊕ c~d==e->f-g": {
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokXor, value: "^"},
+		Token{_type: tokIdent, value: "b"},
+		Token{_type: tokXor, value: "⊕"},
+		Token{_type: tokIdent, value: "c"},
+		Token{_type: tokEquivalence, value: "~"},
+		Token{_type: tokIdent, value: "d"},
+		Token{_type: tokEquivalence, value: "=="},
+		Token{_type: tokIdent, value: "e"},
+		Token{_type: tokImplication, value: "->"},
+		Token{_type: tokIdent, value: "f"},
+		Token{_type: tokDifference, value: "-"},
+		Token{_type: tokIdent, value: "g"},
+		Token{_type: tokEOF},
+	},
+	"a|b ↓ c": {
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokNand, value: "|"},
+		Token{_type: tokIdent, value: "b"},
+		Token{_type: tokNor, value: "↓"},
+		Token{_type: tokIdent, value: "c"},
+		Token{_type: tokEOF},
+	},
+	"a # b": {
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokError, value: "#"},
+	},
+	"a = b": {
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokError, value: "="},
+	},
 	"x + z": {
 		Token{_type: tokIdent, value: "x"},
 		Token{_type: tokUnion, value: "+"},
@@ -100,27 +145,103 @@ var testsLexer = map[string]([]Token){
 	},
 }
 
+// readers which split the input between Read calls in different ways
+var testReaders = map[string]func(string) io.Reader{
+	"Reader": func(s string) io.Reader { return strings.NewReader(s) },
+	"OneByteReader": func(s string) io.Reader {
+		return iotest.OneByteReader(strings.NewReader(s))
+	},
+	"HalfReader": func(s string) io.Reader {
+		return iotest.HalfReader(strings.NewReader(s))
+	},
+	"DataErrReader": func(s string) io.Reader {
+		return iotest.DataErrReader(strings.NewReader(s))
+	},
+}
+
+func checkTokens(t *testing.T, s *Scanner, expected []Token, test string) {
+	for _, tok := range expected {
+		rtok := s.Next()
+		//t.Log("get token:", rtok._type, rtok.value)
+		if rtok._type != tok._type {
+			t.Error("Expected token type", tok._type, "actual:", rtok._type, "test:", test)
+			return
+		}
+		if rtok.value != tok.value {
+			t.Error("Expected value", tok.value, "actual:", rtok.value, "test:", test)
+			return
+		}
+		// don't check offset
+	}
+}
+
 func TestLexer(t *testing.T) {
 	t.Parallel()
 
 	for k, v := range testsLexer {
-		ch := make(chan Token)
-		r := strings.NewReader(k)
+		for name, reader := range testReaders {
+			checkTokens(t, NewScanner(reader(k)), v, name+" "+k)
+		}
+	}
+}
 
-		go Lexer(r, ch)
+func TestScannerPeek(t *testing.T) {
+	t.Parallel()
+	s := NewScanner(strings.NewReader("a + b"))
 
-		for _, tok := range v {
-			rtok := <-ch
-			//t.Log("get token:", rtok._type, rtok.value)
-			if rtok._type != tok._type {
-				t.Error("Expected token type", tok._type, "actual:", rtok._type, "test:", k)
-				break
-			}
-			if rtok.value != tok.value {
-				t.Error("Expected value", tok.value, "actual:", rtok.value, "test:", k)
-				break
+	if tok := s.Peek(); tok._type != tokIdent || tok.value != "a" {
+		t.Error("Expected Identifier a, actual:", tok._type, tok.value)
+	}
+	if tok := s.Peek(); tok._type != tokIdent || tok.value != "a" {
+		t.Error("Peek must not consume, actual:", tok._type, tok.value)
+	}
+	s.Next()
+	if tok := s.Next(); tok._type != tokUnion || tok.offset != 2 {
+		t.Error("Expected Union at 2, actual:", tok._type, "at", tok.offset)
+	}
+	s.putToken(Token{_type: tokConst, value: "1"})
+	if tok := s.Next(); tok._type != tokConst {
+		t.Error("Expected the token put back, actual:", tok._type)
+	}
+	checkTokens(t, s, []Token{
+		Token{_type: tokIdent, value: "b"},
+		Token{_type: tokEOF},
+		Token{_type: tokEOF},
+	}, "a + b")
+}
+
+func TestScannerStopsOnError(t *testing.T) {
+	t.Parallel()
+	s := NewScanner(strings.NewReader("a → b"))
+	checkTokens(t, s, []Token{
+		Token{_type: tokIdent, value: "a"},
+		Token{_type: tokError, value: "→"},
+		Token{_type: tokError, value: "→"},
+	}, "a → b")
+}
+
+func TestScannerLongIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	// identifiers which cross the boundaries of 256 and 4096 bytes
+	for _, prefix := range []int{0, 1, 200, 255, 256, 4000, 4095} {
+		for _, length := range []int{1, 55, 56, 57, 300, 5000} {
+			ident := "x" + strings.Repeat("a1", length/2) + strings.Repeat("z", length%2)
+			input := strings.Repeat(" ", prefix) + ident + "*" + ident + " "
+			for name, reader := range testReaders {
+				s := NewScanner(reader(input))
+				test := name + " " + strconv.Itoa(prefix) + "+" + strconv.Itoa(len(ident))
+
+				if tok := s.Next(); tok.value != ident || tok.offset != TokenOffset(prefix) {
+					t.Error("Expected identifier of length", len(ident), "at", prefix,
+						"actual: length", len(tok.value), "at", tok.offset, "test:", test)
+				}
+				checkTokens(t, s, []Token{
+					Token{_type: tokIntersection, value: "*"},
+					Token{_type: tokIdent, value: ident},
+					Token{_type: tokEOF},
+				}, test)
 			}
-			// don't check offset
 		}
 	}
 }
@@ -123,3 +123,123 @@ func (in IntersectionNode) Calculate(ns Namespace) (bool, error) {
 func (in IntersectionNode) String() string {
 	return "(" + in.LExpr.String() + " * " + in.RExpr.String() + ")"
 }
+
+type NandNode struct {
+	BinaryNodeStruct
+}
+
+func (nd NandNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := nd.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := nd.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return !(lexpr && rexpr), nil
+}
+
+func (nd NandNode) String() string {
+	return "(" + nd.LExpr.String() + " | " + nd.RExpr.String() + ")"
+}
+
+type NorNode struct {
+	BinaryNodeStruct
+}
+
+func (nr NorNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := nr.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := nr.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return !(lexpr || rexpr), nil
+}
+
+func (nr NorNode) String() string {
+	return "(" + nr.LExpr.String() + " ↓ " + nr.RExpr.String() + ")"
+}
+
+type DifferenceNode struct {
+	BinaryNodeStruct
+}
+
+func (dn DifferenceNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := dn.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := dn.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return (lexpr && !rexpr), nil
+}
+
+func (dn DifferenceNode) String() string {
+	return "(" + dn.LExpr.String() + " - " + dn.RExpr.String() + ")"
+}
+
+type XorNode struct {
+	BinaryNodeStruct
+}
+
+func (xn XorNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := xn.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := xn.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return (lexpr != rexpr), nil
+}
+
+func (xn XorNode) String() string {
+	return "(" + xn.LExpr.String() + " ^ " + xn.RExpr.String() + ")"
+}
+
+type EquivalenceNode struct {
+	BinaryNodeStruct
+}
+
+func (en EquivalenceNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := en.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := en.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return (lexpr == rexpr), nil
+}
+
+func (en EquivalenceNode) String() string {
+	return "(" + en.LExpr.String() + " ~ " + en.RExpr.String() + ")"
+}
+
+type ImplicationNode struct {
+	BinaryNodeStruct
+}
+
+func (imn ImplicationNode) Calculate(ns Namespace) (bool, error) {
+	lexpr, err := imn.LExpr.Calculate(ns)
+	if err != nil {
+		return lexpr, err
+	}
+	rexpr, err := imn.RExpr.Calculate(ns)
+	if err != nil {
+		return rexpr, err
+	}
+	return (!lexpr || rexpr), nil
+}
+
+func (imn ImplicationNode) String() string {
+	return "(" + imn.LExpr.String() + " -> " + imn.RExpr.String() + ")"
+}
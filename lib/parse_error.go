@@ -0,0 +1,74 @@
+package boolParser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseError is returned by Parser and ParseString when the input
+// is not a valid statement.
+type ParseError struct {
+	Offset   TokenOffset // byte offset of the offending token
+	Expected []TokenType // token types which were allowed at Offset
+	Found    Token       // the offending token
+	Source   string      // parsed text, empty if unknown
+}
+
+func describeToken(token Token) string {
+	if token.value == "" || token._type == tokEOF {
+		return token._type.String()
+	}
+	return token._type.String() + " " + strconv.Quote(token.value)
+}
+
+func (e *ParseError) Error() string {
+	msg := "position " + strconv.Itoa(int(e.Offset)) + ": "
+	if e.Found._type == tokError {
+		// the lexer has failed, so the expected tokens don't matter
+		msg += "invalid input " + strconv.Quote(e.Found.value)
+	} else if len(e.Expected) > 0 {
+		expected := make([]string, len(e.Expected))
+		for i, t := range e.Expected {
+			expected[i] = t.String()
+		}
+		msg += "expected " + strings.Join(expected, " or ") + ", found " + describeToken(e.Found)
+	} else {
+		msg += "unexpected " + describeToken(e.Found)
+	}
+
+	if e.Source == "" {
+		return msg
+	}
+	return msg + "\n" + e.Caret()
+}
+
+// Caret renders the line of Source which contains Offset
+// and a caret under the offending token:
+//
+//	a + * b
+//	    ^
+func (e *ParseError) Caret() string {
+	offset := int(e.Offset)
+	if offset > len(e.Source) {
+		offset = len(e.Source)
+	}
+	start := strings.LastIndexByte(e.Source[:offset], '\n') + 1
+	end := strings.IndexByte(e.Source[offset:], '\n')
+	if end < 0 {
+		end = len(e.Source)
+	} else {
+		end += offset
+	}
+	line := strings.TrimRight(e.Source[start:end], "\r")
+
+	// keep tabs so the caret stays aligned with the token
+	padding := make([]byte, 0, offset-start)
+	for _, r := range e.Source[start:offset] {
+		if r == '\t' {
+			padding = append(padding, '\t')
+		} else {
+			padding = append(padding, ' ')
+		}
+	}
+	return line + "\n" + string(padding) + "^"
+}
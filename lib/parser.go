@@ -1,10 +1,16 @@
 package boolParser
 
 import (
-	"errors"
 	"strings"
 )
 
+// fail aborts parsing at the current token, Parser recovers it
+// and returns the *ParseError as an error.
+func fail(ts TokenStream, expected ...TokenType) {
+	token := ts.topToken()
+	panic(&ParseError{Offset: token.offset, Expected: expected, Found: token})
+}
+
 func parseIdentifier(ts TokenStream) Node {
 	token := ts.topToken()
 	if token._type != tokIdent {
@@ -47,97 +53,128 @@ func parseExpression(ts TokenStream) Node {
 	}
 	oType := ts.topToken()._type
 	if oType != tokOpeningParenthesis && oType != tokOpeningBraces {
-		// maybe panic ???
-		return nil
+		fail(ts, tokConst, tokIdent, tokNegation, tokOpeningParenthesis, tokOpeningBraces)
 	}
 
 	ts.popToken()
-	if node = parseStatement(ts); node == nil {
-		return nil
-		// panic() // TODO
-	}
+	node = parseStatement(ts)
 	cType := ts.topToken()._type
 	if oType == tokOpeningBraces && cType != tokClosingBraces {
-		return nil
-		// panic() // TODO
+		fail(ts, tokClosingBraces)
 	}
 	if oType == tokOpeningParenthesis && cType != tokClosingParenthesis {
-		return nil
-		// panic() // TODO
+		fail(ts, tokClosingParenthesis)
 	}
 
 	ts.popToken()
 	return node
 }
 
+// parseLeftAssociative parses `operand { op operand }` and folds it to the left:
+// a op b op c == (a op b) op c. newNode returns nil for tokens that
+// are not operators of this precedence level.
+func parseLeftAssociative(ts TokenStream, operand func(TokenStream) Node, newNode func(TokenType) BinaryNode) Node {
+	lExpr := operand(ts)
+
+	for {
+		bn := newNode(ts.topToken()._type)
+		if bn == nil {
+			return lExpr
+		}
+		bn.SetLeftExpression(lExpr)
+		ts.popToken()
+
+		bn.SetRightExpression(operand(ts))
+		lExpr = bn
+	}
+}
+
+// Sheffer stroke is not associative, so a | b | c == (a | b) | c
 func parseIntersectionExpression(ts TokenStream) Node {
-	lExpr := parseExpression(ts)
-	if lExpr == nil {
+	return parseLeftAssociative(ts, parseExpression, func(t TokenType) BinaryNode {
+		switch t {
+		case tokIntersection:
+			return &IntersectionNode{}
+		case tokNand:
+			return &NandNode{}
+		}
 		return nil
-		// panic() // TODO
-	}
+	})
+}
 
-	t_type := ts.topToken()._type
-	var node BinaryNode
-	switch t_type {
-	case tokIntersection:
-		node = &IntersectionNode{}
-	default:
-		return lExpr
-	}
-	node.SetLeftExpression(lExpr)
-	ts.popToken()
-	rExpr := parseIntersectionExpression(ts)
-	if rExpr == nil {
+// union, difference and Peirce arrow share a precedence level:
+// a - b + c == (a - b) + c
+func parseUnionExpression(ts TokenStream) Node {
+	return parseLeftAssociative(ts, parseIntersectionExpression, func(t TokenType) BinaryNode {
+		switch t {
+		case tokUnion:
+			return &UnionNode{}
+		case tokDifference:
+			return &DifferenceNode{}
+		case tokNor:
+			return &NorNode{}
+		}
 		return nil
-		// panic() // TODO
-	}
-	node.SetRightExpression(rExpr)
-	return node
+	})
 }
 
-func parseStatement(ts TokenStream) Node {
-	lExpr := parseIntersectionExpression(ts)
-	if lExpr == nil {
+func parseXorExpression(ts TokenStream) Node {
+	return parseLeftAssociative(ts, parseUnionExpression, func(t TokenType) BinaryNode {
+		if t == tokXor {
+			return &XorNode{}
+		}
 		return nil
-		// panic() // TODO
-	}
+	})
+}
 
-	t_type := ts.topToken()._type
-	var bn BinaryNode
-	switch t_type {
-	case tokUnion:
-		bn = &UnionNode{}
-	default:
+func parseEquivalenceExpression(ts TokenStream) Node {
+	return parseLeftAssociative(ts, parseXorExpression, func(t TokenType) BinaryNode {
+		if t == tokEquivalence {
+			return &EquivalenceNode{}
+		}
+		return nil
+	})
+}
+
+// implication has the lowest priority and is right-associative:
+// a -> b -> c == a -> (b -> c)
+func parseStatement(ts TokenStream) Node {
+	lExpr := parseEquivalenceExpression(ts)
+	if ts.topToken()._type != tokImplication {
 		return lExpr
 	}
-	bn.SetLeftExpression(lExpr)
-	ts.popToken()
 
-	rExpr := parseStatement(ts)
-	if rExpr == nil {
-		return nil
-		// panic() // TODO
-	}
-	bn.SetRightExpression(rExpr)
+	node := &ImplicationNode{}
+	node.SetLeftExpression(lExpr)
 	ts.popToken()
-	return bn
+	node.SetRightExpression(parseStatement(ts))
+	return node
 }
 
+// Parser returns *ParseError if the token stream is not a valid statement
+// or the statement is followed by anything but tokEOF.
 func Parser(ts TokenStream) (node Node, err error) {
-	node = parseStatement(ts)
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(*ParseError)
+			if !ok {
+				panic(r)
+			}
+			node, err = nil, perr
+		}
+	}()
 
-	if node == nil {
-		err = errors.New("fail to parse")
+	node = parseStatement(ts)
+	if ts.topToken()._type != tokEOF {
+		fail(ts, tokEOF)
 	}
 	return
 }
 
 func ParseString(str string) (node Node, err error) {
-	ch := make(chan Token)
-	r := strings.NewReader(str)
-	go Lexer(r, ch)
-
-	ts := NewArrayTokenStream(ch)
-	return Parser(&ts)
+	node, err = Parser(NewScanner(strings.NewReader(str)))
+	if perr, ok := err.(*ParseError); ok {
+		perr.Source = str
+	}
+	return
 }
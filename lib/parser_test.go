@@ -281,23 +281,224 @@ func TestParseStatementPriority(t *testing.T) {
 	checkIdentifier(t, rnode.RExpr, "c")
 }
 
+func TestParseStatementDifference(t *testing.T) {
+	t.Parallel()
+	//a - b * c
+	//(a - (b * c))
+	stack := []*Token{
+		&Token{_type: tokEOF},
+		&Token{_type: tokIdent, value: "c"},
+		&Token{_type: tokIntersection, value: "*"},
+		&Token{_type: tokIdent, value: "b"},
+		&Token{_type: tokDifference, value: "-"},
+		&Token{_type: tokIdent, value: "a"},
+	}
+	ts := &TestTokenStream{stack: stack}
+	node := parseStatement(ts)
+	dnode, ok := node.(*DifferenceNode)
+	if !ok {
+		t.Fatalf("expected Difference, actual %T", node)
+	}
+	checkIdentifier(t, dnode.LExpr, "a")
+	rnode, ok := dnode.RExpr.(*IntersectionNode)
+	if !ok {
+		t.Fatalf("expected Intersection, actual %T", dnode.RExpr)
+	}
+	checkIdentifier(t, rnode.LExpr, "b")
+	checkIdentifier(t, rnode.RExpr, "c")
+}
+
+func TestParseStatementLeftAssociative(t *testing.T) {
+	t.Parallel()
+	//a - b + c
+	//((a - b) + c)
+	stack := []*Token{
+		&Token{_type: tokEOF},
+		&Token{_type: tokIdent, value: "c"},
+		&Token{_type: tokUnion, value: "+"},
+		&Token{_type: tokIdent, value: "b"},
+		&Token{_type: tokDifference, value: "-"},
+		&Token{_type: tokIdent, value: "a"},
+	}
+	ts := &TestTokenStream{stack: stack}
+	node := parseStatement(ts)
+	unode, ok := node.(*UnionNode)
+	if !ok {
+		t.Fatalf("expected Union, actual %T", node)
+	}
+	checkIdentifier(t, unode.RExpr, "c")
+	lnode, ok := unode.LExpr.(*DifferenceNode)
+	if !ok {
+		t.Fatalf("expected Difference, actual %T", unode.LExpr)
+	}
+	checkIdentifier(t, lnode.LExpr, "a")
+	checkIdentifier(t, lnode.RExpr, "b")
+}
+
+func TestDifferenceCalculate(t *testing.T) {
+	t.Parallel()
+	node, err := ParseString("a - b")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, a := range []bool{false, true} {
+		for _, b := range []bool{false, true} {
+			actual, err := node.Calculate(Namespace{"a": a, "b": b})
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if expected := a && !b; actual != expected {
+				t.Error("a =", a, "b =", b, "expected:", expected, "actual:", actual)
+			}
+		}
+	}
+	if s := node.String(); s != "(a - b)" {
+		t.Error("expected: (a - b), actual:", s)
+	}
+}
+
+var binaryOperators = map[string]func(a, b bool) bool{
+	"a + b":  func(a, b bool) bool { return a || b },
+	"a - b":  func(a, b bool) bool { return a && !b },
+	"a * b":  func(a, b bool) bool { return a && b },
+	"a ^ b":  func(a, b bool) bool { return a != b },
+	"a ⊕ b":  func(a, b bool) bool { return a != b },
+	"a ~ b":  func(a, b bool) bool { return a == b },
+	"a == b": func(a, b bool) bool { return a == b },
+	"a -> b": func(a, b bool) bool { return !a || b },
+	"a | b":  func(a, b bool) bool { return !(a && b) },
+	"a ↓ b":  func(a, b bool) bool { return !(a || b) },
+}
+
+func TestBinaryOperatorsCalculate(t *testing.T) {
+	t.Parallel()
+	for str, f := range binaryOperators {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		for _, a := range []bool{false, true} {
+			for _, b := range []bool{false, true} {
+				actual, err := node.Calculate(Namespace{"a": a, "b": b})
+				if err != nil {
+					t.Error(str, err.Error())
+				}
+				if expected := f(a, b); actual != expected {
+					t.Error(str, "a =", a, "b =", b, "expected:", expected, "actual:", actual)
+				}
+			}
+		}
+	}
+}
+
+var priorities = map[string]string{
+	"a -> b ~ c ^ d + e * f": "(a -> (b ~ (c ^ (d + (e * f)))))",
+	"a * b + c ^ d ~ e -> f": "(((((a * b) + c) ^ d) ~ e) -> f)",
+	"a -> b -> c":            "(a -> (b -> c))",
+	"a ^ b ^ c":              "((a ^ b) ^ c)",
+	"a == b ~ c":             "((a ~ b) ~ c)",
+	"(a -> b) ⊕ !c":          "((a -> b) ^ !c)",
+	"a | b | c":              "((a | b) | c)",
+	"a ↓ b ↓ c":              "((a ↓ b) ↓ c)",
+	"a | b * c ↓ d | e":      "(((a | b) * c) ↓ (d | e))",
+	"a * b * c":              "((a * b) * c)",
+}
+
+func TestParsePriorities(t *testing.T) {
+	t.Parallel()
+	for str, expected := range priorities {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		if actual := node.String(); actual != expected {
+			t.Error(str, "expected:", expected, "actual:", actual)
+		}
+		// String() must be parsed back to the same tree
+		again, err := ParseString(node.String())
+		if err != nil {
+			t.Error(node.String(), err.Error())
+			continue
+		}
+		if again.String() != node.String() {
+			t.Error("round trip of", str, "expected:", node.String(), "actual:", again.String())
+		}
+	}
+}
+
 func TestParser(t *testing.T) {
 	t.Parallel()
-	ch := make(chan Token)
 	r := strings.NewReader("a + c * d - !(q \\ 1) ")
-	go Lexer(r, ch)
 
-	ts := NewArrayTokenStream(ch)
-	ast, err := Parser(&ts)
+	ast, err := Parser(NewScanner(r))
 	if err != nil {
 		t.Error(err.Error())
 	}
 	if ast == nil {
 		t.Error("expected AST, actual nil are got")
 	}
-	node, ok := ast.(*UnionNode)
+	node, ok := ast.(*DifferenceNode)
 	if !ok {
-		t.Errorf("expected Union, actual got: %T", ast)
+		t.Fatalf("expected Difference, actual got: %T", ast)
+	}
+	unode, ok := node.LExpr.(*UnionNode)
+	if !ok {
+		t.Fatalf("expected Union, actual got: %T", node.LExpr)
+	}
+	checkIdentifier(t, unode.LExpr, "a")
+	if _, ok := node.RExpr.(NegationNode); !ok {
+		t.Errorf("expected Negation, actual got: %T", node.RExpr)
+	}
+}
+
+var parseErrors = map[string]struct {
+	offset   TokenOffset
+	expected TokenType
+	found    TokenType
+	caret    string
+}{
+	"a + ":       {4, tokIdent, tokEOF, "a + \n    ^"},
+	"(a + b":     {6, tokClosingParenthesis, tokEOF, "(a + b\n      ^"},
+	"[a * b)":    {6, tokClosingBraces, tokClosingParenthesis, "[a * b)\n      ^"},
+	"x ⊕ * y":    {6, tokIdent, tokIntersection, "x ⊕ * y\n    ^"},
+	"!\t-> b":    {2, tokIdent, tokImplication, "!\t-> b\n \t^"},
+	"a +\nb + )": {8, tokOpeningParenthesis, tokClosingParenthesis, "b + )\n    ^"},
+	"a b c":      {2, tokEOF, tokIdent, "a b c\n  ^"},
+	"a)":         {1, tokEOF, tokClosingParenthesis, "a)\n ^"},
+	"(a + b))":   {7, tokEOF, tokClosingParenthesis, "(a + b))\n       ^"},
+	"a + b # c":  {6, tokEOF, tokError, "a + b # c\n      ^"},
+	"a * (b @)":  {7, tokClosingParenthesis, tokError, "a * (b @)\n       ^"},
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+	for str, test := range parseErrors {
+		_, err := ParseString(str)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected *ParseError, actual: %T", str, err)
+			continue
+		}
+		if perr.Offset != test.offset {
+			t.Errorf("%q: expected offset %d, actual: %d", str, test.offset, perr.Offset)
+		}
+		if perr.Found._type != test.found {
+			t.Errorf("%q: expected found %s, actual: %s", str, test.found, perr.Found._type)
+		}
+		expected := false
+		for _, _type := range perr.Expected {
+			expected = expected || _type == test.expected
+		}
+		if !expected {
+			t.Errorf("%q: expected %s in %v", str, test.expected, perr.Expected)
+		}
+		if caret := perr.Caret(); caret != test.caret {
+			t.Errorf("%q: expected caret\n%s\nactual:\n%s", str, test.caret, caret)
+		}
+		if !strings.HasSuffix(perr.Error(), "\n"+test.caret) {
+			t.Errorf("%q: error message has no caret: %s", str, perr.Error())
+		}
 	}
-	checkIdentifier(t, node.LExpr, "a")
 }
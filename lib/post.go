@@ -0,0 +1,88 @@
+package boolParser
+
+import "math/bits"
+
+// PostClass is one of the five Post's closed classes of Boolean functions.
+type PostClass int
+
+const (
+	T0       PostClass = iota // preserving 0
+	T1                        // preserving 1
+	SelfDual                  // self-dual
+	Monotone                  // monotone
+	Linear                    // linear, Zhegalkin polynomial has no products
+)
+
+// PostClassesCount is the number of Post's closed classes.
+const PostClassesCount = 5
+
+var postClassNames = [PostClassesCount]string{"T0", "T1", "S", "M", "L"}
+
+func (c PostClass) String() string {
+	return postClassNames[c]
+}
+
+// PostClasses tells which Post's classes a function belongs to.
+type PostClasses [PostClassesCount]bool
+
+// PostClassesOf finds Post's classes of the function with the given
+// column of the truth table, whose length must be a power of two.
+func PostClassesOf(values []bool) PostClasses {
+	last := len(values) - 1
+	classes := PostClasses{
+		T0:       !values[0],
+		T1:       values[last],
+		SelfDual: true,
+		Monotone: true,
+		Linear:   true,
+	}
+	for row, value := range values {
+		if value == values[last^row] {
+			classes[SelfDual] = false
+		}
+		for bit := 1; bit < len(values); bit <<= 1 {
+			if row&bit == 0 && value && !values[row|bit] {
+				classes[Monotone] = false
+			}
+		}
+	}
+	for m, c := range ZhegalkinCoefficients(values) {
+		if c && bits.OnesCount(uint(m)) > 1 {
+			classes[Linear] = false
+		}
+	}
+	return classes
+}
+
+// Post finds Post's classes of node as a function of its variables.
+// Adding fictitious variables does not change the classes.
+func Post(node Node) (PostClasses, error) {
+	values, err := TruthVector(node, Variables(node))
+	if err != nil {
+		return PostClasses{}, err
+	}
+	return PostClassesOf(values), nil
+}
+
+// Closure returns the classes containing all the functions: by Post's
+// criterion the set of functions is functionally complete if and only if
+// the result is empty.
+func Closure(functions []PostClasses) []PostClass {
+	classes := []PostClass{}
+	for c := PostClass(0); c < PostClassesCount; c++ {
+		all := true
+		for _, f := range functions {
+			all = all && f[c]
+		}
+		if all {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+// Complete reports whether the set of functions is functionally complete
+// by Post's criterion.
+func Complete(functions []PostClasses) bool {
+	return len(Closure(functions)) == 0
+}
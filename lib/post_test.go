@@ -0,0 +1,88 @@
+package boolParser
+
+import (
+	"strings"
+	"testing"
+)
+
+var testsPost = map[string]string{
+	"0":                     "T0 M L",
+	"1":                     "T1 M L",
+	"a":                     "T0 T1 S M L",
+	"!a":                    "S L",
+	"a * b":                 "T0 T1 M",
+	"a + b":                 "T0 T1 M",
+	"a ^ b":                 "T0 L",
+	"a ~ b":                 "T1 L",
+	"a -> b":                "T1",
+	"a | b":                 "",
+	"a ↓ b":                 "",
+	"a * b + a * c + b * c": "T0 T1 S M",
+	"a ^ b ^ c":             "T0 T1 S L",
+	"a + b * 0":             "T0 T1 S M L",
+}
+
+func postString(classes PostClasses) string {
+	str := ""
+	for c := PostClass(0); c < PostClassesCount; c++ {
+		if classes[c] {
+			if str != "" {
+				str += " "
+			}
+			str += c.String()
+		}
+	}
+	return str
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+	for str, expected := range testsPost {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		classes, err := Post(node)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		if actual := postString(classes); actual != expected {
+			t.Errorf("%s: expected %q, actual %q", str, expected, actual)
+		}
+	}
+}
+
+var testsComplete = map[string]bool{
+	"a | b":               true,
+	"a ↓ b":               true,
+	"a * b, a + b":        false,
+	"a * b, a + b, !a":    true,
+	"a * b, a ^ b, 1":     true,
+	"a * b, a ^ b":        false,
+	"a -> b, 0":           true,
+	"a -> b":              false,
+	"a ^ b, a ~ b, !a, 1": false,
+}
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+	for set, expected := range testsComplete {
+		functions := []PostClasses{}
+		for _, str := range strings.Split(set, ", ") {
+			node, err := ParseString(str)
+			if err != nil {
+				t.Fatal(str, err)
+			}
+			classes, err := Post(node)
+			if err != nil {
+				t.Fatal(str, err)
+			}
+			functions = append(functions, classes)
+		}
+		if actual := Complete(functions); actual != expected {
+			t.Errorf("{%s}: expected complete %v, actual %v, closed in %v", set, expected, actual, Closure(functions))
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package boolParser
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// Implicant is a product of literals over an ordered list of variables.
+// Variable i corresponds to bit n-1-i of Value and Free, like in rows of
+// a truth table: bits set in Free are variables absent from the product,
+// the other bits of Value are values of the present variables.
+type Implicant struct {
+	Value int
+	Free  int
+}
+
+// Covers reports whether the implicant is true on the row.
+func (imp Implicant) Covers(row int) bool {
+	return row&^imp.Free == imp.Value
+}
+
+// Literals returns the number of variables in the product of n variables.
+func (imp Implicant) Literals(n int) int {
+	return n - bits.OnesCount(uint(imp.Free))
+}
+
+// Pattern writes the implicant as a string of '0', '1' and '-'.
+func (imp Implicant) Pattern(n int) string {
+	buffer := make([]byte, n)
+	for i := range buffer {
+		bit := 1 << uint(n-1-i)
+		switch {
+		case imp.Free&bit != 0:
+			buffer[i] = '-'
+		case imp.Value&bit != 0:
+			buffer[i] = '1'
+		default:
+			buffer[i] = '0'
+		}
+	}
+	return string(buffer)
+}
+
+// Node returns the product as an intersection of literals, or 1 if it
+// has no literals.
+func (imp Implicant) Node(vars []string) Node {
+	var node Node
+	for i, name := range vars {
+		bit := 1 << uint(len(vars)-1-i)
+		if imp.Free&bit != 0 {
+			continue
+		}
+		var literal Node = Identifier{Name: name}
+		if imp.Value&bit == 0 {
+			literal = NegationNode{expr: literal}
+		}
+		if node == nil {
+			node = literal
+		} else {
+			node = &IntersectionNode{BinaryNodeStruct{LExpr: node, RExpr: literal}}
+		}
+	}
+	if node == nil {
+		return Const{Value: "1"}
+	}
+	return node
+}
+
+// DNF is a minimal disjunctive normal form found by the Quine–McCluskey
+// method with Petrick's method for the cyclic part of the prime implicant
+// chart. When Petrick's method is too expensive the cover is chosen
+// greedily and may be not minimal, then Exact is false.
+type DNF struct {
+	Vars      []string
+	Primes    []Implicant // all prime implicants covering at least one true row
+	Essential []Implicant // primes that are the only cover of some true row
+	Cover     []Implicant // chosen primes, essential ones included
+	Exact     bool        // the cover is known to be minimal
+}
+
+// Node returns the minimal DNF as a union of products.
+func (d *DNF) Node() Node {
+	return sumOfProducts(d.Cover, d.Vars)
+}
+
+func sumOfProducts(implicants []Implicant, vars []string) Node {
+	if len(implicants) == 0 {
+		return Const{Value: "0"}
+	}
+	node := implicants[0].Node(vars)
+	for _, imp := range implicants[1:] {
+		node = &UnionNode{BinaryNodeStruct{LExpr: node, RExpr: imp.Node(vars)}}
+	}
+	return node
+}
+
+// Literals returns the total number of literals in the cover.
+func (d *DNF) Literals() int {
+	count := 0
+	for _, imp := range d.Cover {
+		count += imp.Literals(len(d.Vars))
+	}
+	return count
+}
+
+func (d *DNF) String() string {
+	return Format(d.Node())
+}
+
+// Minimize finds the minimal DNF of node over vars. Rows of the truth
+// table where dontCare is true may take any value; dontCare may be nil.
+func Minimize(node Node, vars []string, dontCare []bool) (*DNF, error) {
+	values, err := TruthVector(node, vars)
+	if err != nil {
+		return nil, err
+	}
+	return MinimizeTruthTable(vars, values, dontCare)
+}
+
+// MinimizeTruthTable finds the minimal DNF of the function with the given
+// column of the truth table over vars, vars[0] being the most significant
+// bit of the row number. Rows where dontCare is true may take any value;
+// dontCare may be nil.
+func MinimizeTruthTable(vars []string, values []bool, dontCare []bool) (*DNF, error) {
+	n := len(vars)
+	if len(values) != 1<<uint(n) || dontCare != nil && len(dontCare) != len(values) {
+		return nil, errors.New("Truth table size does not match the number of variables")
+	}
+	ones := []int{}
+	level := map[Implicant]bool{}
+	for row, value := range values {
+		dc := dontCare != nil && dontCare[row]
+		if value && !dc {
+			ones = append(ones, row)
+		}
+		if value || dc {
+			level[Implicant{Value: row}] = true
+		}
+	}
+
+	d := &DNF{Vars: vars}
+	d.Primes = primeImplicants(level, n, ones)
+	d.Essential, d.Cover, d.Exact = cover(d.Primes, ones, n)
+	return d, nil
+}
+
+// primeImplicants glues implicants differing in one variable until
+// nothing can be glued and returns those that cover at least one of ones.
+func primeImplicants(level map[Implicant]bool, n int, ones []int) []Implicant {
+	primes := []Implicant{}
+	for len(level) > 0 {
+		next := map[Implicant]bool{}
+		glued := map[Implicant]bool{}
+		for imp := range level {
+			for bit := 1; bit < 1<<uint(n); bit <<= 1 {
+				if imp.Free&bit != 0 || imp.Value&bit != 0 {
+					continue
+				}
+				pair := Implicant{Value: imp.Value | bit, Free: imp.Free}
+				if level[pair] {
+					next[Implicant{Value: imp.Value, Free: imp.Free | bit}] = true
+					glued[imp] = true
+					glued[pair] = true
+				}
+			}
+		}
+		for imp := range level {
+			if !glued[imp] && coversAny(imp, ones) {
+				primes = append(primes, imp)
+			}
+		}
+		level = next
+	}
+	sortImplicants(primes, n)
+	return primes
+}
+
+func coversAny(imp Implicant, rows []int) bool {
+	for _, row := range rows {
+		if imp.Covers(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortImplicants orders implicants by their patterns over n variables
+// with '1' < '0' < '-', so that products go in order of variables.
+func sortImplicants(implicants []Implicant, n int) {
+	rank := func(imp Implicant, bit int) int {
+		switch {
+		case imp.Free&bit != 0:
+			return 2
+		case imp.Value&bit != 0:
+			return 0
+		}
+		return 1
+	}
+	sort.Slice(implicants, func(i, j int) bool {
+		for bit := 1 << uint(n-1); bit > 0; bit >>= 1 {
+			ri, rj := rank(implicants[i], bit), rank(implicants[j], bit)
+			if ri != rj {
+				return ri < rj
+			}
+		}
+		return false
+	})
+}
+
+// cover chooses essential primes and then the cheapest cover of the rest
+// of ones: the least number of products and then the least number of
+// literals. The last result is false if the cover was chosen greedily.
+func cover(primes []Implicant, ones []int, n int) ([]Implicant, []Implicant, bool) {
+	chosen := map[Implicant]bool{}
+	essential := []Implicant{}
+	for _, row := range ones {
+		var only Implicant
+		count := 0
+		for _, imp := range primes {
+			if imp.Covers(row) {
+				only = imp
+				count++
+			}
+		}
+		if count == 1 && !chosen[only] {
+			chosen[only] = true
+			essential = append(essential, only)
+		}
+	}
+
+	rest := []int{}
+	for _, row := range ones {
+		covered := false
+		for imp := range chosen {
+			covered = covered || imp.Covers(row)
+		}
+		if !covered {
+			rest = append(rest, row)
+		}
+	}
+	candidates := []Implicant{}
+	for _, imp := range primes {
+		if !chosen[imp] && coversAny(imp, rest) {
+			candidates = append(candidates, imp)
+		}
+	}
+	cyclic, exact := petrick(candidates, rest, n)
+	for _, imp := range cyclic {
+		chosen[imp] = true
+	}
+
+	result := []Implicant{}
+	for _, imp := range primes {
+		if chosen[imp] {
+			result = append(result, imp)
+		}
+	}
+	sortImplicants(essential, n)
+	return essential, result, exact
+}
+
+// maxProducts limits the number of products petrick multiplies out
+// before absorption; the expansion grows exponentially with the number
+// of rows left after essential implicants.
+const maxProducts = 2048
+
+// petrick finds the cheapest subset of candidates covering rows by
+// multiplying out the product of sums of candidates covering each row.
+// Subsets are bit masks, so with more than 64 candidates, or when the
+// expansion exceeds maxProducts, it falls back to the greedy choice of
+// the candidate covering the most rows and reports the result as inexact.
+func petrick(candidates []Implicant, rows []int, n int) ([]Implicant, bool) {
+	if len(rows) == 0 {
+		return nil, true
+	}
+	if len(candidates) > 64 {
+		return greedyCover(candidates, rows), false
+	}
+
+	products := []uint64{0}
+	for _, row := range rows {
+		next := []uint64{}
+		for _, product := range products {
+			for i, imp := range candidates {
+				if imp.Covers(row) {
+					next = append(next, product|1<<uint(i))
+				}
+			}
+		}
+		if len(next) > maxProducts {
+			return greedyCover(candidates, rows), false
+		}
+		products = absorb(next)
+	}
+
+	cost := func(product uint64) (int, int) {
+		literals := 0
+		for i, imp := range candidates {
+			if product&(1<<uint(i)) != 0 {
+				literals += imp.Literals(n)
+			}
+		}
+		return bits.OnesCount64(product), literals
+	}
+	best := products[0]
+	bestCount, bestLiterals := cost(best)
+	for _, product := range products[1:] {
+		count, literals := cost(product)
+		if count < bestCount || count == bestCount && literals < bestLiterals {
+			best, bestCount, bestLiterals = product, count, literals
+		}
+	}
+
+	result := []Implicant{}
+	for i, imp := range candidates {
+		if best&(1<<uint(i)) != 0 {
+			result = append(result, imp)
+		}
+	}
+	return result, true
+}
+
+// absorb removes duplicates and supersets from products: X + X * Y = X.
+func absorb(products []uint64) []uint64 {
+	sort.Slice(products, func(i, j int) bool {
+		ci, cj := bits.OnesCount64(products[i]), bits.OnesCount64(products[j])
+		if ci != cj {
+			return ci < cj
+		}
+		return products[i] < products[j]
+	})
+	result := []uint64{}
+	for _, product := range products {
+		absorbed := false
+		for _, r := range result {
+			if product&r == r {
+				absorbed = true
+				break
+			}
+		}
+		if !absorbed {
+			result = append(result, product)
+		}
+	}
+	return result
+}
+
+func greedyCover(candidates []Implicant, rows []int) []Implicant {
+	result := []Implicant{}
+	for len(rows) > 0 {
+		best, bestCount := 0, -1
+		for i, imp := range candidates {
+			count := 0
+			for _, row := range rows {
+				if imp.Covers(row) {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = i, count
+			}
+		}
+		result = append(result, candidates[best])
+		rest := []int{}
+		for _, row := range rows {
+			if !candidates[best].Covers(row) {
+				rest = append(rest, row)
+			}
+		}
+		rows = rest
+	}
+	return result
+}
+
+// Report lists prime implicants, essential ones and the cover, one per
+// line, with patterns over vars and marks: "E" for essential, "C" for
+// the other chosen ones. Marks are upper case letters, so they are not
+// taken for operators or variables of a formula.
+func (d *DNF) Report() string {
+	essential := map[Implicant]bool{}
+	for _, imp := range d.Essential {
+		essential[imp] = true
+	}
+	chosen := map[Implicant]bool{}
+	for _, imp := range d.Cover {
+		chosen[imp] = true
+	}
+	lines := []string{}
+	for _, imp := range d.Primes {
+		mark := " "
+		if essential[imp] {
+			mark = "E"
+		} else if chosen[imp] {
+			mark = "C"
+		}
+		lines = append(lines, imp.Pattern(len(d.Vars))+" "+mark+" "+Format(imp.Node(d.Vars)))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
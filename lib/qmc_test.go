@@ -0,0 +1,178 @@
+package boolParser
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+func rowsToTable(n int, rows ...int) []bool {
+	values := make([]bool, 1<<uint(n))
+	for _, row := range rows {
+		values[row] = true
+	}
+	return values
+}
+
+func TestMinimizeTruthTable(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d"}
+	d, err := MinimizeTruthTable(vars, rowsToTable(4, 4, 8, 10, 11, 12, 15), rowsToTable(4, 9, 14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Primes) != 4 {
+		t.Errorf("expected 4 prime implicants, actual %v", d.Report())
+	}
+	essential := map[string]bool{}
+	for _, imp := range d.Essential {
+		essential[imp.Pattern(4)] = true
+	}
+	if len(essential) != 2 || !essential["-100"] || !essential["1-1-"] {
+		t.Errorf("expected essential -100 and 1-1-, actual %v", d.Report())
+	}
+	if len(d.Cover) != 3 || d.Literals() != 7 {
+		t.Errorf("expected 3 products with 7 literals, actual %s", d.String())
+	}
+}
+
+func TestMinimizeCyclic(t *testing.T) {
+	t.Parallel()
+	d, err := MinimizeTruthTable([]string{"a", "b", "c"}, rowsToTable(3, 0, 1, 2, 5, 6, 7), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Primes) != 6 || len(d.Essential) != 0 {
+		t.Errorf("expected 6 primes and no essential ones, actual %v", d.Report())
+	}
+	if len(d.Cover) != 3 || d.Literals() != 6 {
+		t.Errorf("expected 3 products with 6 literals, actual %s", d.String())
+	}
+}
+
+func TestMinimizeConstants(t *testing.T) {
+	t.Parallel()
+	constants := map[string]string{
+		"a * !a":    "0",
+		"a + !a":    "1",
+		"a * b + a": "a",
+		"a -> b":    "!a + b",
+		"!(a ↓ b)":  "a + b",
+		"a ^ b ^ 1": "a * b + !a * !b",
+	}
+	for str, expected := range constants {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		d, err := Minimize(node, []string{"a", "b"}, nil)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		if actual := d.String(); actual != expected {
+			t.Errorf("%s: expected %s, actual %s", str, expected, actual)
+		}
+	}
+}
+
+// bruteCost finds the least number of products and then literals of
+// a cover of ones by a subset of primes.
+func bruteCost(primes []Implicant, ones []int, n int) (int, int) {
+	bestCount, bestLiterals := len(primes)+1, 0
+	for set := 0; set < 1<<uint(len(primes)); set++ {
+		covered := true
+		for _, row := range ones {
+			found := false
+			for i, imp := range primes {
+				found = found || set&(1<<uint(i)) != 0 && imp.Covers(row)
+			}
+			covered = covered && found
+		}
+		if !covered {
+			continue
+		}
+		count, literals := bits.OnesCount(uint(set)), 0
+		for i, imp := range primes {
+			if set&(1<<uint(i)) != 0 {
+				literals += imp.Literals(n)
+			}
+		}
+		if count < bestCount || count == bestCount && literals < bestLiterals {
+			bestCount, bestLiterals = count, literals
+		}
+	}
+	return bestCount, bestLiterals
+}
+
+func TestMinimizeRandom(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d"}
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 300; i++ {
+		node := randomNode(rnd, vars, 4)
+		dontCare := make([]bool, 16)
+		for row := range dontCare {
+			dontCare[row] = rnd.Intn(5) == 0
+		}
+		d, err := Minimize(node, vars, dontCare)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := TruthVector(node, vars)
+		actual, err := TruthVector(d.Node(), vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ones := []int{}
+		for row := range expected {
+			if !dontCare[row] && expected[row] != actual[row] {
+				t.Fatalf("%s: minimal DNF %s differs at row %d", Format(node), d.String(), row)
+			}
+			if !dontCare[row] && expected[row] {
+				ones = append(ones, row)
+			}
+		}
+		if len(d.Primes) > 16 {
+			continue
+		}
+		if !d.Exact {
+			t.Errorf("%s: expected an exact cover, actual %s", Format(node), d.String())
+		}
+		count, literals := bruteCost(d.Primes, ones, len(vars))
+		if len(d.Cover) != count || d.Literals() != literals {
+			t.Errorf("%s: expected %d products with %d literals, actual %s", Format(node), count, literals, d.String())
+		}
+	}
+}
+
+func TestMinimizeManyVariables(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(7))
+	for _, n := range []int{7, 8} {
+		vars := []string{"a", "b", "c", "d", "e", "f", "g", "h"}[:n]
+		for i := 0; i < 5; i++ {
+			values := make([]bool, 1<<uint(n))
+			for row := range values {
+				values[row] = rnd.Intn(2) == 1
+			}
+			d, err := MinimizeTruthTable(vars, values, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := TruthVector(d.Node(), vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for row := range values {
+				if values[row] != actual[row] {
+					t.Fatalf("%d variables: minimal DNF %s differs at row %d", n, d.String(), row)
+				}
+			}
+			if d.Exact {
+				t.Errorf("%d variables: expected a greedy cover for %d primes", n, len(d.Primes))
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package boolParser
+
+import "strings"
+
+// exprKind is the kind of a node of the normalised expression used by
+// Simplify.
+type exprKind int
+
+const (
+	exprVar exprKind = iota
+	exprConst
+	exprAnd
+	exprOr
+	exprXor
+	exprEquivalence
+)
+
+// expr is an expression in negation normal form: negations are applied
+// to variables only, unions and intersections are n-ary.
+type expr struct {
+	kind  exprKind
+	name  string // variable name
+	value bool   // negated for a variable, value for a constant
+	args  []*expr
+	key   string // canonical text to compare subexpressions
+}
+
+func newVar(name string, negated bool) *expr {
+	e := &expr{kind: exprVar, name: name, value: negated, key: name}
+	if negated {
+		e.key = "!" + name
+	}
+	return e
+}
+
+func newConst(value bool) *expr {
+	if value {
+		return &expr{kind: exprConst, value: true, key: "1"}
+	}
+	return &expr{kind: exprConst, key: "0"}
+}
+
+var exprOperators = map[exprKind]string{
+	exprAnd:         " * ",
+	exprOr:          " + ",
+	exprXor:         " ^ ",
+	exprEquivalence: " ~ ",
+}
+
+func newOp(kind exprKind, args ...*expr) *expr {
+	keys := make([]string, len(args))
+	for i, arg := range args {
+		keys[i] = arg.key
+	}
+	return &expr{kind: kind, args: args, key: "(" + strings.Join(keys, exprOperators[kind]) + ")"}
+}
+
+// normalize converts node to negation normal form, negating it if
+// negate is set. Difference, implication, NAND and NOR are expressed
+// through unions and intersections, negations are pushed to variables
+// by De Morgan's laws.
+func normalize(node Node, negate bool) *expr {
+	switch n := node.(type) {
+	case Identifier:
+		return newVar(n.Name, negate)
+	case Const:
+		return newConst((n.Value == "1") != negate)
+	case NegationNode:
+		return normalize(n.expr, !negate)
+	case *NegationNode:
+		return normalize(n.expr, !negate)
+	}
+
+	bn := node.(BinaryNode)
+	l, r := bn.LeftExpression(), bn.RightExpression()
+	and, or := exprAnd, exprOr
+	if negate {
+		and, or = exprOr, exprAnd
+	}
+	switch node.(type) {
+	case *UnionNode:
+		return newOp(or, normalize(l, negate), normalize(r, negate))
+	case *IntersectionNode:
+		return newOp(and, normalize(l, negate), normalize(r, negate))
+	case *NandNode:
+		return newOp(or, normalize(l, !negate), normalize(r, !negate))
+	case *NorNode:
+		return newOp(and, normalize(l, !negate), normalize(r, !negate))
+	case *DifferenceNode:
+		return newOp(and, normalize(l, negate), normalize(r, !negate))
+	case *ImplicationNode:
+		return newOp(or, normalize(l, !negate), normalize(r, negate))
+	case *XorNode:
+		if negate {
+			return newOp(exprEquivalence, normalize(l, false), normalize(r, false))
+		}
+		return newOp(exprXor, normalize(l, false), normalize(r, false))
+	case *EquivalenceNode:
+		if negate {
+			return newOp(exprXor, normalize(l, false), normalize(r, false))
+		}
+		return newOp(exprEquivalence, normalize(l, false), normalize(r, false))
+	}
+	panic("unknown node type")
+}
+
+// negation returns the negation of e in negation normal form.
+func (e *expr) negation() *expr {
+	switch e.kind {
+	case exprVar:
+		return newVar(e.name, !e.value)
+	case exprConst:
+		return newConst(!e.value)
+	case exprXor:
+		return newOp(exprEquivalence, e.args...)
+	case exprEquivalence:
+		return newOp(exprXor, e.args...)
+	}
+	args := make([]*expr, len(e.args))
+	for i, arg := range e.args {
+		args[i] = arg.negation()
+	}
+	if e.kind == exprAnd {
+		return newOp(exprOr, args...)
+	}
+	return newOp(exprAnd, args...)
+}
+
+// simplify applies one pass of simplification rules bottom-up.
+func (e *expr) simplify() *expr {
+	switch e.kind {
+	case exprAnd, exprOr:
+		return simplifyJunction(e.kind, e.args)
+	case exprXor, exprEquivalence:
+		return simplifyXor(e.kind, e.args[0].simplify(), e.args[1].simplify())
+	}
+	return e
+}
+
+// simplifyJunction simplifies an intersection (kind exprAnd) or a union
+// (kind exprOr) of args.
+func simplifyJunction(kind exprKind, args []*expr) *expr {
+	// 0 is absorbing and 1 is neutral for an intersection,
+	// and the other way round for a union
+	absorbing := kind == exprOr
+	dual := exprOr
+	if kind == exprOr {
+		dual = exprAnd
+	}
+
+	flat := []*expr{}
+	seen := map[string]bool{}
+	var add func(arg *expr)
+	add = func(arg *expr) {
+		switch {
+		case arg.kind == kind:
+			for _, a := range arg.args {
+				add(a)
+			}
+		case arg.kind == exprConst && arg.value != absorbing:
+		case !seen[arg.key]:
+			seen[arg.key] = true
+			flat = append(flat, arg)
+		}
+	}
+	for _, arg := range args {
+		add(arg.simplify())
+	}
+
+	// an absorbing constant or complementary arguments
+	for _, arg := range flat {
+		if arg.kind == exprConst || seen[arg.negation().key] {
+			return newConst(absorbing)
+		}
+	}
+
+	// absorption: a * (a + b) = a, a + a * b = a
+	result := []*expr{}
+	for _, arg := range flat {
+		absorbed := false
+		if arg.kind == dual {
+			for _, a := range arg.args {
+				if seen[a.key] {
+					absorbed = true
+					break
+				}
+			}
+		}
+		if !absorbed {
+			result = append(result, arg)
+		}
+	}
+
+	switch len(result) {
+	case 0:
+		return newConst(!absorbing)
+	case 1:
+		return result[0]
+	}
+	return newOp(kind, result...)
+}
+
+// simplifyXor simplifies l ^ r (kind exprXor) or l ~ r (kind exprEquivalence).
+func simplifyXor(kind exprKind, l, r *expr) *expr {
+	// a ~ b = !(a ^ b), so an equivalence is simplified as XOR
+	// and the result is negated
+	invert := kind == exprEquivalence
+	result := func(e *expr) *expr {
+		if invert {
+			return e.negation().simplify()
+		}
+		return e
+	}
+	switch {
+	case l.kind == exprConst && r.kind == exprConst:
+		return result(newConst(l.value != r.value))
+	case l.kind == exprConst && !l.value:
+		return result(r)
+	case l.kind == exprConst:
+		return result(r.negation().simplify())
+	case r.kind == exprConst && !r.value:
+		return result(l)
+	case r.kind == exprConst:
+		return result(l.negation().simplify())
+	case l.key == r.key:
+		return result(newConst(false))
+	case l.key == r.negation().key:
+		return result(newConst(true))
+	}
+	return newOp(kind, l, r)
+}
+
+func (e *expr) node() Node {
+	switch e.kind {
+	case exprVar:
+		if e.value {
+			return NegationNode{expr: Identifier{Name: e.name}}
+		}
+		return Identifier{Name: e.name}
+	case exprConst:
+		if e.value {
+			return Const{Value: "1"}
+		}
+		return Const{Value: "0"}
+	}
+	node := e.args[0].node()
+	for _, arg := range e.args[1:] {
+		nodes := BinaryNodeStruct{LExpr: node, RExpr: arg.node()}
+		switch e.kind {
+		case exprAnd:
+			node = &IntersectionNode{nodes}
+		case exprOr:
+			node = &UnionNode{nodes}
+		case exprXor:
+			node = &XorNode{nodes}
+		case exprEquivalence:
+			node = &EquivalenceNode{nodes}
+		}
+	}
+	return node
+}
+
+// Simplify returns an expression equivalent to node built of unions,
+// intersections, XOR, equivalences and negations of variables. It folds
+// constants (a * 0 = 0, a + 1 = 1), removes double negations, repeated
+// and complementary arguments, applies absorption (a + a * b = a) and
+// De Morgan's laws and flattens nested unions and intersections.
+// The result is not necessarily minimal.
+func Simplify(node Node) Node {
+	e := normalize(node, false)
+	for {
+		next := e.simplify()
+		if next.key == e.key {
+			return next.node()
+		}
+		e = next
+	}
+}
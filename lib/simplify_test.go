@@ -0,0 +1,127 @@
+package boolParser
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var testsSimplify = map[string]string{
+	"a * 0":                 "0",
+	"a + 1":                 "1",
+	"a * 1 + 0":             "a",
+	"!!a":                   "a",
+	"!!!a":                  "!a",
+	"a * a":                 "a",
+	"a + b + a":             "a + b",
+	"a * !a":                "0",
+	"a + !a * 1":            "1",
+	"a * (a + b)":           "a",
+	"a + a * b":             "a",
+	"(b + c) * (a + b) * b": "b",
+	"!(a + b)":              "!a * !b",
+	"!(a * b)":              "!a + !b",
+	"!(a * !(b + c))":       "!a + b + c",
+	"(a + b) + (c + d)":     "a + b + c + d",
+	"a * (b * (c * d))":     "a * b * c * d",
+	"a - b":                 "a * !b",
+	"a -> b":                "!a + b",
+	"a | b":                 "!a + !b",
+	"a ↓ b":                 "!a * !b",
+	"!(a ^ b)":              "a ~ b",
+	"a ^ 0":                 "a",
+	"a ^ 1":                 "!a",
+	"a ~ 0":                 "!a",
+	"a ^ a":                 "0",
+	"a ~ !a":                "0",
+	"(a + 0) ^ (b * 1)":     "a ^ b",
+	"!(a ~ b) + c":          "(a ^ b) + c",
+}
+
+func TestSimplify(t *testing.T) {
+	t.Parallel()
+	for str, expected := range testsSimplify {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		if actual := Format(Simplify(node)); actual != expected {
+			t.Errorf("%s: expected %s, actual %s", str, expected, actual)
+		}
+	}
+}
+
+// randomNode builds a random expression over vars with at most depth
+// levels of binary operations.
+func randomNode(rnd *rand.Rand, vars []string, depth int) Node {
+	if depth == 0 || rnd.Intn(4) == 0 {
+		switch rnd.Intn(6) {
+		case 0:
+			return Const{Value: []string{"0", "1"}[rnd.Intn(2)]}
+		case 1:
+			return NegationNode{expr: randomNode(rnd, vars, depth)}
+		}
+		return Identifier{Name: vars[rnd.Intn(len(vars))]}
+	}
+	nodes := BinaryNodeStruct{LExpr: randomNode(rnd, vars, depth-1), RExpr: randomNode(rnd, vars, depth-1)}
+	switch rnd.Intn(9) {
+	case 0:
+		return &UnionNode{nodes}
+	case 1:
+		return &IntersectionNode{nodes}
+	case 2:
+		return &NandNode{nodes}
+	case 3:
+		return &NorNode{nodes}
+	case 4:
+		return &DifferenceNode{nodes}
+	case 5:
+		return &XorNode{nodes}
+	case 6:
+		return &EquivalenceNode{nodes}
+	case 7:
+		return &ImplicationNode{nodes}
+	}
+	return NegationNode{expr: &UnionNode{nodes}}
+}
+
+func TestSimplifyEquivalent(t *testing.T) {
+	t.Parallel()
+	vars := []string{"a", "b", "c", "d"}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		node := randomNode(rnd, vars, 5)
+		simple := Simplify(node)
+		expected, err := TruthVector(node, vars)
+		if err != nil {
+			t.Fatal(node, err)
+		}
+		actual, err := TruthVector(simple, vars)
+		if err != nil {
+			t.Fatal(simple, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("%s simplified to non-equivalent %s", Format(node), Format(simple))
+		}
+		if again := Simplify(simple); Format(again) != Format(simple) {
+			t.Errorf("%s: simplification is not idempotent: %s, %s", Format(node), Format(simple), Format(again))
+		}
+	}
+}
+
+func TestFormatParsesBack(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		node := randomNode(rnd, []string{"a", "b", "c"}, 4)
+		str := Format(node)
+		parsed, err := ParseString(str)
+		if err != nil {
+			t.Fatal(str, err)
+		}
+		if parsed.String() != node.String() {
+			t.Fatalf("%s parsed as %s, expected %s", str, parsed.String(), node.String())
+		}
+	}
+}
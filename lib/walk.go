@@ -0,0 +1,30 @@
+package boolParser
+
+// Walk calls visit for node and then for each of its subexpressions,
+// left to right.
+func Walk(node Node, visit func(Node)) {
+	visit(node)
+	switch n := node.(type) {
+	case NegationNode:
+		Walk(n.expr, visit)
+	case *NegationNode:
+		Walk(n.expr, visit)
+	case BinaryNode:
+		Walk(n.LeftExpression(), visit)
+		Walk(n.RightExpression(), visit)
+	}
+}
+
+// Variables returns names of identifiers used in node
+// in order of their first occurrence.
+func Variables(node Node) []string {
+	seen := map[string]bool{}
+	vars := []string{}
+	Walk(node, func(n Node) {
+		if id, ok := n.(Identifier); ok && !seen[id.Name] {
+			seen[id.Name] = true
+			vars = append(vars, id.Name)
+		}
+	})
+	return vars
+}
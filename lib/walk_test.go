@@ -0,0 +1,27 @@
+package boolParser
+
+import (
+	"strings"
+	"testing"
+)
+
+var testsVariables = map[string]string{
+	"1":                     "",
+	"x":                     "x",
+	"!(a + b) * a ^ 0 -> c": "a b c",
+	"[z | y] ↓ !!x - y":     "z y x",
+}
+
+func TestVariables(t *testing.T) {
+	t.Parallel()
+	for str, expected := range testsVariables {
+		node, err := ParseString(str)
+		if err != nil {
+			t.Error(str, err.Error())
+			continue
+		}
+		if actual := strings.Join(Variables(node), " "); actual != expected {
+			t.Error(str, "expected:", expected, "actual:", actual)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/apcera/termtables"
+	"github.com/horpto/toi/lib"
+)
+
+// MealyMachine — автомат Мили, заданный схемой.
+// Состояние — набор значений задержек, буква входного алфавита — набор
+// значений входов, выходная буква — набор значений выходов.
+// Наборы кодируются числами: первая переменная — старший бит.
+type MealyMachine struct {
+	Inputs  []string // имена входов
+	Outputs []string // имена выходов
+	Delays  []string // имена задержек
+	Names   []string // имена состояний, если они не наборы значений задержек
+	Initial int      // начальное состояние
+	Next    [][]int  // Next[q][a] — состояние после подачи a в состоянии q
+	Output  [][]int  // Output[q][a] — выход при подаче a в состоянии q
+}
+
+// delays возвращает имена задержек в порядке объявления.
+func (s Scheme) delays() []string {
+	return append([]string{}, s.Delays...)
+}
+
+func setBits(namespace boolParser.Namespace, names []string, value int) {
+	for i, name := range names {
+		namespace[name] = value&(1<<uint(len(names)-1-i)) != 0
+	}
+}
+
+func getBits(namespace boolParser.Namespace, names []string) int {
+	value := 0
+	for _, name := range names {
+		value <<= 1
+		if namespace[name] {
+			value |= 1
+		}
+	}
+	return value
+}
+
+func bitsToString(value int, width int) string {
+	buffer := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buffer[i] = '0' + byte(value&1)
+		value >>= 1
+	}
+	return string(buffer)
+}
+
+func (s Scheme) createMealyMachine() (*MealyMachine, error) {
+	m := &MealyMachine{
+		Inputs:  s.Inputs,
+		Outputs: s.Outputs,
+		Delays:  s.delays(),
+	}
+	m.Initial = getBits(s.Init, m.Delays)
+
+	states := 1 << uint(len(m.Delays))
+	letters := m.LettersCount()
+	// строка таблицы - состояние в старших битах, буква в младших
+	vars := append(append([]string{}, m.Delays...), m.Inputs...)
+	res, err := s.calculateAll(vars)
+	if err != nil {
+		return nil, err
+	}
+	row := func(names []string, i int) int {
+		value := 0
+		for _, name := range names {
+			value <<= 1
+			if res[name][i] {
+				value |= 1
+			}
+		}
+		return value
+	}
+
+	m.Next = make([][]int, states)
+	m.Output = make([][]int, states)
+	for q := 0; q < states; q++ {
+		m.Next[q] = make([]int, letters)
+		m.Output[q] = make([]int, letters)
+		for a := 0; a < letters; a++ {
+			m.Next[q][a] = row(m.Delays, q*letters+a)
+			m.Output[q][a] = row(m.Outputs, q*letters+a)
+		}
+	}
+	return m, nil
+}
+
+func (m *MealyMachine) StatesCount() int {
+	return len(m.Next)
+}
+
+func (m *MealyMachine) LettersCount() int {
+	return 1 << uint(len(m.Inputs))
+}
+
+// StateName — значения задержек в состоянии q, например "01".
+func (m *MealyMachine) StateName(q int) string {
+	if m.Names != nil {
+		return m.Names[q]
+	}
+	if len(m.Delays) == 0 {
+		return "-"
+	}
+	return bitsToString(q, len(m.Delays))
+}
+
+func (m *MealyMachine) LetterName(a int) string {
+	return bitsToString(a, len(m.Inputs))
+}
+
+func (m *MealyMachine) OutputName(b int) string {
+	return bitsToString(b, len(m.Outputs))
+}
+
+// String выводит таблицу переходов и выходов:
+// строка — состояние, столбец — входная буква,
+// в ячейке — следующее состояние/выход.
+func (m *MealyMachine) String() string {
+	table := termtables.CreateTable()
+	table.SetModeTerminal()
+
+	if m.Names != nil {
+		table.AddHeaders("q")
+	} else {
+		table.AddHeaders(strings.Join(m.Delays, ""))
+	}
+	inputs := strings.Join(m.Inputs, "")
+	for a := 0; a < m.LettersCount(); a++ {
+		table.AddHeaders(inputs + "=" + m.LetterName(a))
+	}
+
+	for q := range m.Next {
+		row := table.AddRow()
+		row.AddCell(m.StateName(q))
+		for a, next := range m.Next[q] {
+			row.AddCell(m.StateName(next) + "/" + m.OutputName(m.Output[q][a]))
+		}
+	}
+	return table.Render()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/horpto/toi/lib"
+)
+
+func TestMealyMachineTables(t *testing.T) {
+	s, err := createSchemeFromFile(filepath.Join("testdata", "unix.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.createMealyMachine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// y = x ^ z, z' = y: оба выхода и переход - x ^ z
+	next := [][]int{{0, 1}, {1, 0}}
+	if !reflect.DeepEqual(m.Next, next) || !reflect.DeepEqual(m.Output, next) {
+		t.Errorf("expected next and output %v, actual %v and %v", next, m.Next, m.Output)
+	}
+	if m.Initial != 0 || m.StateName(1) != "1" || m.LetterName(1) != "1" {
+		t.Errorf("unexpected initial state %d or names %s, %s", m.Initial, m.StateName(1), m.LetterName(1))
+	}
+}
+
+// TestMealyMachineMatchesCalculate сверяет кодирование автомата
+// (первая переменная - старший бит) с вычислением такта через calculate.
+func TestMealyMachineMatchesCalculate(t *testing.T) {
+	for _, name := range []string{"adder.txt", "delays.txt", "unordered.txt"} {
+		s, err := createSchemeFromFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := s.createMealyMachine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Initial != getBits(s.Init, m.Delays) {
+			t.Errorf("%s: expected initial state %d, actual %d", name, getBits(s.Init, m.Delays), m.Initial)
+		}
+		for q := 0; q < m.StatesCount(); q++ {
+			for a := 0; a < m.LettersCount(); a++ {
+				namespace := boolParser.Namespace{}
+				setBits(namespace, m.Delays, q)
+				setBits(namespace, m.Inputs, a)
+				res, err := s.calculate(namespace)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if next := getBits(res, m.Delays); m.Next[q][a] != next {
+					t.Errorf("%s: state %s, letter %s: expected next %d, actual %d",
+						name, m.StateName(q), m.LetterName(a), next, m.Next[q][a])
+				}
+				if out := getBits(res, m.Outputs); m.Output[q][a] != out {
+					t.Errorf("%s: state %s, letter %s: expected output %d, actual %d",
+						name, m.StateName(q), m.LetterName(a), out, m.Output[q][a])
+				}
+			}
+		}
+	}
+}
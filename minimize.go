@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Minimization — результат минимизации автомата Мили
+// разбиением состояний на классы эквивалентности (алгоритм Мура).
+type Minimization struct {
+	Machine *MealyMachine // исходный автомат
+	Classes [][]int       // классы эквивалентных состояний исходного автомата
+	ClassOf []int         // ClassOf[q] — номер класса состояния q
+	Minimal *MealyMachine // автомат, состояния которого — классы
+	// задержки, от значения которых поведение автомата не зависит
+	RedundantDelays []string
+}
+
+// refine разбивает состояния на классы по ключу key(q);
+// классы нумеруются в порядке появления первого состояния.
+func refine(states int, key func(q int) string) []int {
+	classOf := make([]int, states)
+	numbers := map[string]int{}
+	for q := 0; q < states; q++ {
+		k := key(q)
+		n, ok := numbers[k]
+		if !ok {
+			n = len(numbers)
+			numbers[k] = n
+		}
+		classOf[q] = n
+	}
+	return classOf
+}
+
+func countClasses(classOf []int) int {
+	count := 0
+	for _, c := range classOf {
+		if c >= count {
+			count = c + 1
+		}
+	}
+	return count
+}
+
+func (m *MealyMachine) minimize() *Minimization {
+	states := m.StatesCount()
+
+	// 0-эквивалентность: одинаковые выходы на каждую входную букву
+	classOf := refine(states, func(q int) string {
+		return fmt.Sprint(m.Output[q])
+	})
+	for {
+		// (k+1)-эквивалентность: k-эквивалентные состояния,
+		// переходящие по каждой букве в k-эквивалентные
+		next := refine(states, func(q int) string {
+			key := fmt.Sprint(classOf[q])
+			for _, n := range m.Next[q] {
+				key += "," + fmt.Sprint(classOf[n])
+			}
+			return key
+		})
+		if countClasses(next) == countClasses(classOf) {
+			break
+		}
+		classOf = next
+	}
+
+	classes := make([][]int, countClasses(classOf))
+	for q, c := range classOf {
+		classes[c] = append(classes[c], q)
+	}
+
+	minimal := &MealyMachine{
+		Inputs:  m.Inputs,
+		Outputs: m.Outputs,
+		Initial: classOf[m.Initial],
+		Names:   make([]string, len(classes)),
+		Next:    make([][]int, len(classes)),
+		Output:  make([][]int, len(classes)),
+	}
+	for c, class := range classes {
+		// переходы всех состояний класса ведут в одни и те же классы
+		q := class[0]
+		minimal.Names[c] = fmt.Sprintf("q%d", c)
+		minimal.Next[c] = make([]int, len(m.Next[q]))
+		for a, n := range m.Next[q] {
+			minimal.Next[c][a] = classOf[n]
+		}
+		minimal.Output[c] = append([]int{}, m.Output[q]...)
+	}
+
+	redundant := []string{}
+	for i, delay := range m.Delays {
+		bit := 1 << uint(len(m.Delays)-1-i)
+		matters := false
+		for q := 0; q < states; q++ {
+			if classOf[q] != classOf[q^bit] {
+				matters = true
+				break
+			}
+		}
+		if !matters {
+			redundant = append(redundant, delay)
+		}
+	}
+
+	return &Minimization{
+		Machine:         m,
+		Classes:         classes,
+		ClassOf:         classOf,
+		Minimal:         minimal,
+		RedundantDelays: redundant,
+	}
+}
+
+// RequiredDelays — сколько задержек достаточно,
+// чтобы закодировать состояния минимального автомата.
+func (mn *Minimization) RequiredDelays() int {
+	delays := 0
+	for 1<<uint(delays) < len(mn.Classes) {
+		delays++
+	}
+	return delays
+}
+
+func (mn *Minimization) String() string {
+	buffer := "Классы эквивалентных состояний:\n"
+	for c, class := range mn.Classes {
+		names := make([]string, len(class))
+		for i, q := range class {
+			names[i] = mn.Machine.StateName(q)
+		}
+		buffer += mn.Minimal.StateName(c) + " = {" + strings.Join(names, ", ") + "}\n"
+	}
+	buffer += "Минимальный автомат:\n"
+	buffer += mn.Minimal.String()
+
+	buffer += fmt.Sprintf("Достаточно задержек: %d из %d\n", mn.RequiredDelays(), len(mn.Machine.Delays))
+	if len(mn.RedundantDelays) > 0 {
+		buffer += "Лишние задержки: " + strings.Join(mn.RedundantDelays, ", ") + "\n"
+	}
+	return buffer
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMinimizeRedundantDelay(t *testing.T) {
+	// z хранит свое значение и ни на что не влияет
+	text := "input: x\noutput: y\nmemory: q, z\ninit: q=1, z=1\ny: x * q\nq: x\nz: z\n"
+	s, err := parseScheme("scheme.txt", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.createMealyMachine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mn := m.minimize()
+
+	if classes := [][]int{{0, 1}, {2, 3}}; !reflect.DeepEqual(mn.Classes, classes) {
+		t.Errorf("expected classes %v, actual %v", classes, mn.Classes)
+	}
+	if classOf := []int{0, 0, 1, 1}; !reflect.DeepEqual(mn.ClassOf, classOf) {
+		t.Errorf("expected class numbers %v, actual %v", classOf, mn.ClassOf)
+	}
+	if !reflect.DeepEqual(mn.RedundantDelays, []string{"z"}) || mn.RequiredDelays() != 1 {
+		t.Errorf("expected redundant z and 1 required delay, actual %v and %d", mn.RedundantDelays, mn.RequiredDelays())
+	}
+
+	minimal := mn.Minimal
+	// начальное состояние 11 лежит во втором классе
+	if m.Initial != 3 || minimal.Initial != 1 {
+		t.Errorf("expected initial states 3 and q1, actual %d and %d", m.Initial, minimal.Initial)
+	}
+	if next := [][]int{{0, 1}, {0, 1}}; !reflect.DeepEqual(minimal.Next, next) {
+		t.Errorf("expected minimal next %v, actual %v", next, minimal.Next)
+	}
+	if output := [][]int{{0, 0}, {0, 1}}; !reflect.DeepEqual(minimal.Output, output) {
+		t.Errorf("expected minimal output %v, actual %v", output, minimal.Output)
+	}
+	if names := []string{"q0", "q1"}; !reflect.DeepEqual(minimal.Names, names) {
+		t.Errorf("expected names %v, actual %v", names, minimal.Names)
+	}
+}
+
+func TestMinimizeMinimalMachine(t *testing.T) {
+	for _, name := range []string{"unix.txt", "adder.txt"} {
+		s, err := createSchemeFromFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := s.createMealyMachine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mn := m.minimize()
+		if classes := [][]int{{0}, {1}}; !reflect.DeepEqual(mn.Classes, classes) {
+			t.Errorf("%s: expected classes %v, actual %v", name, classes, mn.Classes)
+		}
+		if len(mn.RedundantDelays) != 0 || mn.RequiredDelays() != 1 {
+			t.Errorf("%s: expected no redundant delays and 1 required, actual %v and %d",
+				name, mn.RedundantDelays, mn.RequiredDelays())
+		}
+		if mn.Minimal.Initial != mn.ClassOf[m.Initial] {
+			t.Errorf("%s: expected initial class %d, actual %d", name, mn.ClassOf[m.Initial], mn.Minimal.Initial)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// dependencies возвращает для каждой переменной с формулой выходы,
+// значения которых в том же такте нужны для ее вычисления.
+// Задержки в формулах означают их текущие значения, известные
+// к началу такта, поэтому зависимостей от задержек нет.
+func (s Scheme) dependencies() map[string][]string {
+	deps := make(map[string][]string, len(s.Memory))
+	for name, node := range s.Memory {
+		deps[name] = []string{}
+		for _, v := range boolParser.Variables(node) {
+			if s.isOutput(v) {
+				deps[name] = append(deps[name], v)
+			}
+		}
+	}
+	return deps
+}
+
+// loopError - выходы, зависящие друг от друга в одном такте по кругу;
+// первое имя повторяется в конце.
+type loopError struct {
+	cycle []string
+}
+
+func (e loopError) Error() string {
+	return "Combinational loop: " + strings.Join(e.cycle, " -> ")
+}
+
+// evaluationOrder возвращает порядок вычисления формул в такте:
+// каждая формула вычисляется после выходов, от которых она зависит.
+// Если выходы зависят друг от друга по кругу, возвращается ошибка
+// с путем по этому кругу.
+func (s Scheme) evaluationOrder() ([]string, error) {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	deps := s.dependencies()
+	state := make(map[string]int, len(deps))
+	order := make([]string, 0, len(deps))
+	path := []string{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case inProgress:
+			// путь от первого вхождения name и есть цикл
+			for i, v := range path {
+				if v == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return loopError{cycle}
+				}
+			}
+		}
+		state[name] = inProgress
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	// выходы в порядке объявления, затем задержки
+	names := append([]string{}, s.Outputs...)
+	names = append(names, s.delays()...)
+	for _, name := range names {
+		if _, ok := deps[name]; !ok {
+			continue
+		}
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
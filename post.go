@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/apcera/termtables"
+	"github.com/horpto/toi/lib"
+)
+
+// postTable строит таблицу Поста для формул: "+" - функция лежит
+// в классе, "-" - не лежит. Последняя строка - вывод о полноте
+// системы формул по критерию Поста.
+func postTable(names []string, nodes []boolParser.Node) (string, error) {
+	table := termtables.CreateTable()
+	table.SetModeTerminal()
+	table.AddHeaders("")
+	for c := boolParser.PostClass(0); c < boolParser.PostClassesCount; c++ {
+		table.AddHeaders(c.String())
+	}
+
+	functions := make([]boolParser.PostClasses, len(nodes))
+	for i, node := range nodes {
+		classes, err := boolParser.Post(node)
+		if err != nil {
+			return "", err
+		}
+		functions[i] = classes
+
+		row := table.AddRow()
+		row.AddCell(names[i])
+		for _, in := range classes {
+			if in {
+				row.AddCell("+")
+			} else {
+				row.AddCell("-")
+			}
+		}
+	}
+
+	conclusion := "Система функционально полна\n"
+	if closure := boolParser.Closure(functions); len(closure) > 0 {
+		classes := make([]string, len(closure))
+		for i, c := range closure {
+			classes[i] = c.String()
+		}
+		conclusion = "Система не полна: все функции лежат в классах " + strings.Join(classes, ", ") + "\n"
+	}
+	return table.Render() + conclusion, nil
+}
+
+// postReport строит таблицу Поста для формул выходов и задержек схемы.
+func (s Scheme) postReport() (string, error) {
+	names := []string{}
+	nodes := []boolParser.Node{}
+	for _, v := range s.formulas() {
+		names = append(names, v+": "+s.source(v))
+		nodes = append(nodes, s.Memory[v])
+	}
+	return postTable(names, nodes)
+}
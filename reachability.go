@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/apcera/termtables"
+)
+
+// Reachability — состояния автомата, достижимые из начального.
+type Reachability struct {
+	Machine   *MealyMachine
+	Reachable []bool  // Reachable[q] — достижимо ли состояние q
+	Words     [][]int // кратчайшее входное слово, приводящее в q, или nil
+}
+
+// reachability обходит автомат в ширину из начального состояния,
+// поэтому найденные слова кратчайшие, а среди них - наименьшие
+// в лексикографическом порядке.
+func (m *MealyMachine) reachability() *Reachability {
+	r := &Reachability{
+		Machine:   m,
+		Reachable: make([]bool, m.StatesCount()),
+		Words:     make([][]int, m.StatesCount()),
+	}
+	r.Reachable[m.Initial] = true
+	r.Words[m.Initial] = []int{}
+
+	queue := []int{m.Initial}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+		for a, next := range m.Next[q] {
+			if r.Reachable[next] {
+				continue
+			}
+			r.Reachable[next] = true
+			r.Words[next] = append(append([]int{}, r.Words[q]...), a)
+			queue = append(queue, next)
+		}
+	}
+	return r
+}
+
+// WordName — входное слово в виде "0110";
+// если входов несколько, буквы разделяются пробелами.
+func (m *MealyMachine) WordName(word []int) string {
+	if len(word) == 0 {
+		return "ε"
+	}
+	letters := make([]string, len(word))
+	for i, a := range word {
+		letters[i] = m.LetterName(a)
+	}
+	if len(m.Inputs) > 1 {
+		return strings.Join(letters, " ")
+	}
+	return strings.Join(letters, "")
+}
+
+func (r *Reachability) String() string {
+	table := termtables.CreateTable()
+	table.SetModeTerminal()
+	table.AddHeaders(strings.Join(r.Machine.Delays, ""), "достижимо", "кратчайшее слово")
+
+	for q, reachable := range r.Reachable {
+		row := table.AddRow()
+		row.AddCell(r.Machine.StateName(q))
+		if reachable {
+			row.AddCell("да")
+			row.AddCell(r.Machine.WordName(r.Words[q]))
+		} else {
+			row.AddCell("нет")
+			row.AddCell("")
+		}
+	}
+	return table.Render()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReachability(t *testing.T) {
+	// p' = q, q' = x, r хранит 1: состояния с r = 0 недостижимы
+	text := "input: x\noutput: y\nmaxdelays: 3\nmemory: p, q, r\ninit: r=1\ny: p\np: q\nq: x\nr: r\n"
+	s, err := parseScheme("scheme.txt", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.createMealyMachine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := m.reachability()
+
+	reachable := []bool{false, true, false, true, false, true, false, true}
+	if !reflect.DeepEqual(r.Reachable, reachable) {
+		t.Errorf("expected reachable %v, actual %v", reachable, r.Reachable)
+	}
+	words := [][]int{nil, {}, nil, {1}, nil, {1, 0}, nil, {1, 1}}
+	if !reflect.DeepEqual(r.Words, words) {
+		t.Errorf("expected words %v, actual %v", words, r.Words)
+	}
+	if name := m.WordName(r.Words[5]); name != "10" {
+		t.Errorf("expected word 10, actual %s", name)
+	}
+	if name := m.WordName(r.Words[1]); name != "ε" {
+		t.Errorf("expected empty word ε, actual %s", name)
+	}
+
+	tt, err := s.createTruthTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// строки - x, p, q, r; состояние - младшие биты номера строки
+	for i, unreachable := range tt.unreachable {
+		if expected := i&1 == 0; unreachable != expected {
+			t.Errorf("row %d: expected unreachable %v, actual %v", i, expected, unreachable)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/apcera/termtables"
 	"github.com/horpto/toi/lib"
@@ -12,153 +16,398 @@ type Node interface {
 }
 
 type Scheme struct {
-	In     string          // имя входного аргумента
-	Out    string          // имя выходного аргумента
-	Memory map[string]Node // словарь: имя переменной - как высчитывается.
+	Inputs  []string          // имена входов
+	Outputs []string          // имена выходов
+	Delays  []string          // имена задержек в порядке объявления
+	Memory  map[string]Node   // словарь: имя переменной - как высчитывается.
+	Sources map[string]string // формулы в том виде, в котором они заданы
+	Init    map[string]bool   // начальные значения задержек, не указанные равны 0
+
+	MaxDelays int // наибольшее допустимое число задержек, 0 - без ограничений
+
+	// комментарии перед строками файла схемы без '#': ключ - имя
+	// переменной для формулы, имя раздела вроде "input" или "" для
+	// конца файла
+	Comments map[string][]string
+	// комментарии в конце строк файла схемы, ключи те же
+	LineComments map[string]string
+
+	order []string // порядок вычисления формул в такте, см. evaluationOrder
 }
 
-func newScheme(in string, out string, mems map[string]string) (*Scheme, error) {
-	if _, ok := mems[in]; ok {
-		return nil, errors.New("Input variable '" + in + "' has formula")
+// newScheme создает схему; переменные с формулой, не объявленные
+// ни выходами, ни задержками, считаются задержками и добавляются
+// после объявленных. Выходы среди задержек пропускаются.
+func newScheme(inputs []string, outputs []string, delays []string, mems map[string]string, init map[string]bool) (*Scheme, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("Scheme has no input variables")
+	}
+	if len(outputs) == 0 {
+		return nil, errors.New("Scheme has no output variables")
+	}
+	declared := map[string]bool{}
+	for _, in := range inputs {
+		if declared[in] {
+			return nil, errors.New("Variable '" + in + "' is declared twice")
+		}
+		declared[in] = true
+	}
+	for _, out := range outputs {
+		if declared[out] {
+			return nil, errors.New("Variable '" + out + "' is declared twice")
+		}
+		declared[out] = true
+		if _, ok := mems[out]; !ok {
+			return nil, errors.New("Output variable '" + out + "' has no formula")
+		}
+	}
+	// файлы, записанные прежними версиями, перечисляли выходы
+	// в memory: вместе с задержками - такие имена пропускаются
+	kept := []string{}
+	for _, delay := range delays {
+		if !contains(outputs, delay) {
+			kept = append(kept, delay)
+		}
+	}
+	delays = kept
+	for _, delay := range delays {
+		if declared[delay] {
+			return nil, errors.New("Variable '" + delay + "' is declared twice")
+		}
+		declared[delay] = true
+		if _, ok := mems[delay]; !ok {
+			return nil, errors.New("Formula for '" + delay + "' not defined")
+		}
 	}
-	if _, ok := mems[out]; !ok {
-		return nil, errors.New("Output variable '" + out + "' has no formula")
+	undeclared := []string{}
+	for k := range mems {
+		if !declared[k] {
+			undeclared = append(undeclared, k)
+		}
 	}
+	sort.Strings(undeclared)
+	delays = append(delays, undeclared...)
 
 	memory := make(map[string]Node, len(mems))
+	sources := make(map[string]string, len(mems))
 	for k, v := range mems {
 		mnode, err := boolParser.ParseString(v)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Formula for '%s' is invalid: %w", k, err)
 		}
 		memory[k] = mnode
+		sources[k] = strings.TrimSpace(v)
+	}
+	if init == nil {
+		init = map[string]bool{}
 	}
-	s := &Scheme{In: in, Out: out, Memory: memory}
+	s := &Scheme{
+		Inputs:       inputs,
+		Outputs:      outputs,
+		Delays:       delays,
+		Memory:       memory,
+		Sources:      sources,
+		Init:         init,
+		MaxDelays:    defaultMaxDelays,
+		Comments:     map[string][]string{},
+		LineComments: map[string]string{},
+	}
+	order, err := s.evaluationOrder()
+	if err != nil {
+		return nil, err
+	}
+	s.order = order
 	return s, nil
 }
 
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scheme) isOutput(name string) bool {
+	return contains(s.Outputs, name)
+}
+
+// String записывает схему в формате файла схемы. Порядок строк
+// не зависит от словарей: входы, выходы, задержки, начальные значения,
+// затем формулы выходов и задержек в порядке объявления.
 func (s *Scheme) String() (buffer string) {
-	buffer += "input: " + s.In + "\n"
-	buffer += "output: " + s.Out + "\n"
+	line := func(key string, text string) {
+		for _, comment := range s.Comments[key] {
+			buffer += commentText(comment) + "\n"
+		}
+		if comment, ok := s.LineComments[key]; ok {
+			text += " " + commentText(comment)
+		}
+		buffer += text + "\n"
+	}
 
-	memories := ""
-	vars := ""
-	for v, node := range s.Memory {
-		memories += v + ","
-		vars += v + ": " + node.String() + "\n"
+	line("input", "input: "+strings.Join(s.Inputs, ", "))
+	line("output", "output: "+strings.Join(s.Outputs, ", "))
+	if s.MaxDelays != defaultMaxDelays {
+		line("maxdelays", "maxdelays: "+strconv.Itoa(s.MaxDelays))
+	}
+	delays := s.delays()
+	if len(delays) > 0 {
+		line("memory", "memory: "+strings.Join(delays, ", "))
+	}
+	if len(s.Init) > 0 {
+		line("init", "init: "+initToString(s.Init, delays))
+	}
+	for _, v := range s.formulas() {
+		line(v, v+": "+s.source(v))
 	}
-	if memories != "" {
-		buffer += "memory: " + memories + "\n"
-		buffer += vars
+	for _, comment := range s.Comments[""] {
+		buffer += commentText(comment) + "\n"
 	}
 	return buffer
 }
 
-func (s Scheme) calculate(namespace boolParser.Namespace) (boolParser.Namespace, error) {
-	if _, ok := namespace[s.Out]; ok {
-		return nil, errors.New("namespace contains output var: " + s.Out)
+// commentText записывает комментарий в формате файла схемы.
+func commentText(comment string) string {
+	if comment == "" {
+		return "#"
 	}
-	node, ok := s.Memory[s.Out]
-	if !ok {
-		return nil, errors.New("Had no formula for output var: " + s.Out)
+	return "# " + comment
+}
+
+// source возвращает формулу переменной в том виде, в котором она задана.
+func (s Scheme) source(name string) string {
+	if source, ok := s.Sources[name]; ok {
+		return source
 	}
-	out, err := node.Calculate(namespace)
-	if err != nil {
-		return nil, err
+	return s.Memory[name].String()
+}
+
+// initToString записывает начальные значения задержек в виде "z=1, q=0"
+// в порядке delays.
+func initToString(init map[string]bool, delays []string) string {
+	values := []string{}
+	for _, k := range delays {
+		if v, ok := init[k]; ok {
+			values = append(values, k+"="+boolToString(v))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// simplifiedFormulas записывает упрощенные формулы выходов и задержек
+// в виде строк "имя: формула".
+func (s Scheme) simplifiedFormulas() string {
+	buffer := ""
+	for _, v := range s.formulas() {
+		buffer += v + ": " + boolParser.Format(boolParser.Simplify(s.Memory[v])) + "\n"
+	}
+	return buffer
+}
+
+// calculate вычисляет за один такт выходы и новые значения задержек
+// в порядке s.order: значение выхода сразу доступно следующим формулам,
+// а задержки в формулах остаются равными своим текущим значениям.
+func (s Scheme) calculate(namespace boolParser.Namespace) (boolParser.Namespace, error) {
+	for _, out := range s.Outputs {
+		if _, ok := namespace[out]; ok {
+			return nil, errors.New("namespace contains output var: " + out)
+		}
 	}
-	namespace[s.Out] = out
+	defer func() {
+		for _, out := range s.Outputs {
+			delete(namespace, out)
+		}
+	}()
 
 	memory := make(boolParser.Namespace, len(s.Memory))
-	memory[s.Out] = out
-	for x, n := range s.Memory {
-		r, err := n.Calculate(namespace)
+	for _, name := range s.order {
+		r, err := s.Memory[name].Calculate(namespace)
 		if err != nil {
-			delete(namespace, s.Out)
-			return memory, err
+			return nil, err
+		}
+		memory[name] = r
+		if s.isOutput(name) {
+			namespace[name] = r
 		}
-		memory[x] = r
 	}
-	delete(namespace, s.Out)
 	return memory, nil
 }
 
-func (s Scheme) calculateOutputWord(signals []bool) ([]bool, error) {
-	out := make([]bool, len(signals))
-	namespace := make(map[string]bool, len(s.Memory))
-	for k, _ := range s.Memory {
-		namespace[k] = false
+func (s Scheme) calculateOutputWord(signals [][]bool) ([][]bool, error) {
+	return s.calculateOutputWordFrom(s.Init, signals)
+}
+
+// calculateOutputWordFrom вычисляет выходное слово, начиная
+// с заданных значений задержек; не указанные задержки равны 0.
+// Буква входного слова - значения входов в порядке Inputs,
+// буква выходного слова - значения выходов в порядке Outputs.
+// Формулы компилируются один раз: переменные - индексы в env,
+// где сначала идут входы, затем задержки, затем выходы.
+func (s Scheme) calculateOutputWordFrom(init map[string]bool, signals [][]bool) ([][]bool, error) {
+	delays := s.delays()
+	slots := make(map[string]int, len(s.Inputs)+len(s.Memory))
+	for _, names := range [][]string{s.Inputs, delays, s.Outputs} {
+		for _, name := range names {
+			slots[name] = len(slots)
+		}
+	}
+	env := make([]bool, len(slots))
+	for _, name := range delays {
+		env[slots[name]] = init[name]
 	}
-	for i, signal := range signals {
-		namespace[s.In] = signal
-		delete(namespace, s.Out)
 
-		res, err := s.calculate(namespace)
+	// programs[i] вычисляет s.order[i] и записывает значение в targets[i]:
+	// выход сразу в env, задержку - в next до конца такта
+	programs := make([]boolParser.Program, len(s.order))
+	targets := make([]*bool, len(s.order))
+	next := make([]bool, len(env))
+	for i, name := range s.order {
+		program, err := boolParser.Compile(s.Memory[name], slots)
 		if err != nil {
 			return nil, err
 		}
-		out[i] = res[s.Out]
-		namespace = res
+		programs[i] = program
+		if s.isOutput(name) {
+			targets[i] = &env[slots[name]]
+		} else {
+			targets[i] = &next[slots[name]]
+		}
+	}
+	firstDelay, firstOutput := len(s.Inputs), len(s.Inputs)+len(delays)
+
+	out := make([][]bool, len(signals))
+	bits := make([]bool, len(signals)*len(s.Outputs))
+	for i, signal := range signals {
+		if len(signal) != len(s.Inputs) {
+			return nil, fmt.Errorf("Letter %d of input word has %d values, expected %d", i, len(signal), len(s.Inputs))
+		}
+		copy(env, signal)
+		for j, program := range programs {
+			*targets[j] = program(env)
+		}
+		copy(env[firstDelay:firstOutput], next[firstDelay:firstOutput])
+		out[i] = bits[i*len(s.Outputs) : (i+1)*len(s.Outputs)]
+		copy(out[i], env[firstOutput:])
 	}
 	return out, nil
 }
 
+// calculateBits - то же, что calculate, но сразу для 64 наборов значений:
+// бит i маски переменной - ее значение в i-м наборе.
+func (s Scheme) calculateBits(namespace boolParser.BitNamespace) (boolParser.BitNamespace, error) {
+	for _, out := range s.Outputs {
+		if _, ok := namespace[out]; ok {
+			return nil, errors.New("namespace contains output var: " + out)
+		}
+	}
+	defer func() {
+		for _, out := range s.Outputs {
+			delete(namespace, out)
+		}
+	}()
+
+	memory := make(boolParser.BitNamespace, len(s.Memory))
+	for _, name := range s.order {
+		r, err := boolParser.CalculateBits(s.Memory[name], namespace)
+		if err != nil {
+			return nil, err
+		}
+		memory[name] = r
+		if s.isOutput(name) {
+			namespace[name] = r
+		}
+	}
+	return memory, nil
+}
+
+// calculateAll вычисляет за один такт выходы и новые значения задержек
+// на всех наборах значений vars. Результат - значения каждой формулы
+// в порядке строк таблицы истинности, vars[0] - старший бит номера строки.
+func (s Scheme) calculateAll(vars []string) (map[string][]bool, error) {
+	rows := 1 << uint(len(vars))
+	values := make(map[string][]bool, len(s.Memory))
+	for name := range s.Memory {
+		values[name] = make([]bool, rows)
+	}
+	namespace := make(boolParser.BitNamespace, len(vars))
+	for block := 0; block*boolParser.Lanes < rows; block++ {
+		boolParser.Assignments(namespace, vars, block)
+		res, err := s.calculateBits(namespace)
+		if err != nil {
+			return nil, err
+		}
+		first := block * boolParser.Lanes
+		for name, mask := range res {
+			column := values[name]
+			for lane := 0; lane < boolParser.Lanes && first+lane < rows; lane++ {
+				column[first+lane] = mask&(1<<uint(lane)) != 0
+			}
+		}
+	}
+	return values, nil
+}
+
 func (s Scheme) createTruthTable() (*TruthTable, error) {
 	// Фиксируем порядок имен переменных,
 	// чтобы при итерации назначать новые значения
-	memory := []string{}
-	for k, _ := range s.Memory {
-		if k == s.In || k == s.Out {
-			continue
-		}
-		memory = append(memory, k)
-	}
-	inputs := append([]string{}, s.In)
+	memory := s.delays()
+	inputs := append([]string{}, s.Inputs...)
 	inputs = append(inputs, memory...)
-	outputs := append([]string{}, s.Out)
+	outputs := append([]string{}, s.Outputs...)
 	outputs = append(outputs, memory...)
 
-	tableLength := 1 << uint32(len(s.Memory))
+	tableLength := 1 << uint32(len(inputs))
 	vars := make([][]bool, tableLength)
 	values := make([][]bool, tableLength)
+	unreachable := make([]bool, tableLength)
+
+	m, err := s.createMealyMachine()
+	if err != nil {
+		return nil, err
+	}
+	reachability := m.reachability()
+
+	res, err := s.calculateAll(inputs)
+	if err != nil {
+		return nil, err
+	}
 
 	for i := 0; i < tableLength; i++ {
-		namespace := make(map[string]bool, len(inputs))
 		varsRow := make([]bool, len(inputs))
 		c := i
 		for j := len(inputs) - 1; j >= 0; j-- {
-			v := c&1 == 1
+			varsRow[j] = c&1 == 1
 			c = c >> 1
-			namespace[inputs[j]] = v
-			varsRow[j] = v
-		}
-
-		res, err := s.calculate(namespace)
-		if err != nil {
-			return nil, err
 		}
 
 		valuesRow := make([]bool, len(outputs))
-		for i, v := range outputs {
-			valuesRow[i] = res[v]
+		for j, v := range outputs {
+			valuesRow[j] = res[v][i]
 		}
 
 		vars[i] = varsRow
 		values[i] = valuesRow
+		// младшие биты номера строки - значения задержек, то есть состояние
+		unreachable[i] = !reachability.Reachable[i&(m.StatesCount()-1)]
 	}
 	tt := &TruthTable{
-		inputs:  inputs,
-		outputs: outputs,
-		vars:    vars,
-		values:  values,
+		inputs:      inputs,
+		outputs:     outputs,
+		vars:        vars,
+		values:      values,
+		unreachable: unreachable,
 	}
 	return tt, nil
 }
 
 type TruthTable struct {
-	inputs  []string
-	outputs []string
-	vars    [][]bool
-	values  [][]bool
+	inputs      []string
+	outputs     []string
+	vars        [][]bool
+	values      [][]bool
+	unreachable []bool // строки с состоянием, недостижимым из начального
 }
 
 func boolToString(b bool) string {
@@ -178,6 +427,13 @@ func (tt *TruthTable) String() string {
 	for _, v := range tt.outputs {
 		table.AddHeaders(v)
 	}
+	hasUnreachable := false
+	for _, u := range tt.unreachable {
+		hasUnreachable = hasUnreachable || u
+	}
+	if hasUnreachable {
+		table.AddHeaders("")
+	}
 
 	// assume len(tt.inputs) == tt.vars[0] and len(tt.output) == len(tt.outputs)
 	// and actually len(tt.inputs) == len(tt.output)
@@ -189,6 +445,11 @@ func (tt *TruthTable) String() string {
 		for _, v := range tt.values[i] {
 			row.AddCell(boolToString(v))
 		}
+		if hasUnreachable && tt.unreachable[i] {
+			row.AddCell("недостижимо")
+		} else if hasUnreachable {
+			row.AddCell("")
+		}
 	}
 
 	return table.Render()
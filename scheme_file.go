@@ -0,0 +1,316 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// loadError — ошибка в файле схемы.
+type loadError struct {
+	file string
+	line int // 0, если ошибка не относится к конкретной строке
+	msg  string
+}
+
+func (e loadError) Error() string {
+	if e.line == 0 {
+		return e.file + ": " + e.msg
+	}
+	return e.file + ":" + strconv.Itoa(e.line) + ": " + e.msg
+}
+
+// loadErrors — все ошибки, найденные в файле схемы.
+type loadErrors []loadError
+
+func (es loadErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// parseList разбирает список имен через запятую.
+func parseList(line string) []string {
+	names := []string{}
+	for _, name := range strings.Split(line, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseInit разбирает начальные значения задержек вида "z=1, q=0".
+func parseInit(line string) (map[string]bool, error) {
+	init := map[string]bool{}
+	for _, pair := range strings.Split(line, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return nil, errors.New("Fail to parse initial value: " + pair)
+		}
+		name := strings.TrimSpace(parts[0])
+		switch strings.TrimSpace(parts[1]) {
+		case "0":
+			init[name] = false
+		case "1":
+			init[name] = true
+		default:
+			return nil, errors.New("Initial value of '" + name + "' must be 0 or 1")
+		}
+	}
+	return init, nil
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		alpha := ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+		if !alpha && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// diagnosticLine возвращает строку файла, к которой относится замечание:
+// заголовок раздела, если замечание о нем, иначе строку формулы.
+func diagnosticLine(s *Scheme, d Diagnostic, lineOf map[string]int) int {
+	if line, ok := lineOf[d.Section+":"]; ok {
+		return line
+	}
+	if d.Section == "memory" {
+		// задержки не объявлены в memory:, их число задает maxdelays:
+		// или первая формула задержки
+		if line, ok := lineOf["maxdelays:"]; ok {
+			return line
+		}
+		return lineOf[s.delays()[0]]
+	}
+	return lineOf[d.Var]
+}
+
+// splitComment отделяет комментарий от строки; текст
+// комментария возвращается без '#'.
+func splitComment(line string) (string, string, bool) {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i+1:]), true
+	}
+	return line, "", false
+}
+
+func createSchemeFromFile(fileName string) (*Scheme, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseScheme(fileName, f)
+}
+
+// parseScheme читает схему в текстовом формате:
+//
+//	# комментарий относится к следующей строке
+//	input: x
+//	output: y
+//	maxdelays: 2
+//	memory: z
+//	init: z=1
+//	y: x + z # комментарий в конце строки
+//	z: !y
+//
+// Строки могут заканчиваться на \r\n, \n или \r, пустые строки
+// пропускаются. Возвращаются сразу все найденные ошибки
+// с номерами строк.
+func parseScheme(fileName string, r io.Reader) (*Scheme, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(string(content))
+
+	errs := loadErrors{}
+	fail := func(line int, format string, args ...interface{}) {
+		errs = append(errs, loadError{file: fileName, line: line, msg: fmt.Sprintf(format, args...)})
+	}
+
+	var inputs, outputs []string
+	memory := []string{}
+	exprs := map[string]string{}
+	init := map[string]bool{}
+	maxDelays := -1
+	lineOf := map[string]int{} // строка заголовка или формулы переменной
+	// имена из input:, output: и memory: в порядке объявления
+	type declaration struct {
+		name   string
+		line   int
+		memory bool
+	}
+	declarations := []declaration{}
+	declare := func(line int, names []string, memory bool) {
+		for _, name := range names {
+			declarations = append(declarations, declaration{name, line, memory})
+		}
+	}
+
+	// комментарии на отдельных строках относятся к следующей строке
+	comments := map[string][]string{}
+	lineComments := map[string]string{}
+	pending := []string{}
+
+	for i, line := range strings.Split(text, "\n") {
+		n := i + 1
+		line, comment, commented := splitComment(line)
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" {
+			if commented {
+				pending = append(pending, comment)
+			}
+			continue
+		}
+
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			fail(n, "expected header or 'name: formula', found %q", line)
+			continue
+		}
+		name := strings.TrimSpace(line[:colon])
+		value := strings.TrimSpace(line[colon+1:])
+
+		key := name + ":"
+		if prev, ok := lineOf[key]; ok && key != "memory:" {
+			fail(n, "'%s' is already declared at line %d", name, prev)
+			continue
+		}
+		switch name {
+		case "input":
+			if inputs = parseList(value); len(inputs) == 0 {
+				fail(n, "no input variables")
+			}
+			declare(n, inputs, false)
+		case "output":
+			if outputs = parseList(value); len(outputs) == 0 {
+				fail(n, "no output variables")
+			}
+			declare(n, outputs, false)
+		case "memory":
+			names := parseList(value)
+			memory = append(memory, names...)
+			declare(n, names, true)
+		case "init":
+			values, err := parseInit(value)
+			if err != nil {
+				fail(n, "%s", err.Error())
+			}
+			for k, v := range values {
+				init[k] = v
+			}
+		case "maxdelays":
+			if maxDelays, err = strconv.Atoi(value); err != nil || maxDelays < 0 {
+				fail(n, "maxdelays must be a non-negative number, found %q", value)
+			}
+		default:
+			key = name
+			if !isIdentifier(name) {
+				fail(n, "invalid variable name %q", name)
+				continue
+			}
+			if prev, ok := lineOf[key]; ok {
+				fail(n, "formula for '%s' is already defined at line %d", name, prev)
+				continue
+			}
+			if _, err := boolParser.ParseString(value); err != nil {
+				fail(n, "formula for '%s' is invalid: %s", name, err.Error())
+			}
+			exprs[name] = value
+		}
+
+		if _, ok := lineOf[key]; !ok {
+			lineOf[key] = n
+		}
+		if len(pending) > 0 {
+			comments[name] = append(comments[name], pending...)
+			pending = []string{}
+		}
+		if commented {
+			lineComments[name] = comment
+		}
+	}
+	if len(pending) > 0 {
+		comments[""] = pending
+	}
+
+	// объявления проверяются после чтения всего файла,
+	// так как output: может идти после memory:
+	if _, ok := lineOf["input:"]; !ok {
+		fail(0, "no input variables")
+	}
+	if _, ok := lineOf["output:"]; !ok {
+		fail(0, "no output variables")
+	}
+	declaredAt := map[string]int{}
+	for _, d := range declarations {
+		// старые файлы перечисляли выходы в memory:, см. newScheme
+		if d.memory && contains(outputs, d.name) {
+			continue
+		}
+		if prev, ok := declaredAt[d.name]; ok {
+			fail(d.line, "variable '%s' is already declared at line %d", d.name, prev)
+			continue
+		}
+		declaredAt[d.name] = d.line
+		if _, ok := exprs[d.name]; !ok && d.memory {
+			fail(d.line, "formula for '%s' is not defined", d.name)
+		}
+	}
+	for _, out := range outputs {
+		if _, ok := exprs[out]; !ok {
+			fail(declaredAt[out], "output variable '%s' has no formula", out)
+		}
+	}
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			return errs[i].line < errs[j].line
+		})
+		return nil, errs
+	}
+
+	s, err := newScheme(inputs, outputs, memory, exprs, init)
+	if err != nil {
+		line := 0
+		if loop, ok := err.(loopError); ok {
+			line = lineOf[loop.cycle[0]]
+		}
+		return nil, loadErrors{{file: fileName, line: line, msg: err.Error()}}
+	}
+	if maxDelays >= 0 {
+		s.MaxDelays = maxDelays
+	}
+	s.Comments = comments
+	s.LineComments = lineComments
+
+	for _, d := range s.Validate() {
+		if d.Warning {
+			continue
+		}
+		fail(diagnosticLine(s, d, lineOf), "%s", d.String())
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return s, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Форматы файлов схемы.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// schemeDocument - схема в виде, удобном для JSON и YAML.
+// Порядок задержек хранится отдельно от формул, так как
+// ключи словарей записываются отсортированными.
+// MaxDelays и комментарии - метаданные схемы.
+type schemeDocument struct {
+	Inputs   []string          `json:"inputs" yaml:"inputs"`
+	Outputs  []string          `json:"outputs" yaml:"outputs"`
+	Delays   []string          `json:"delays,omitempty" yaml:"delays,omitempty"`
+	Formulas map[string]string `json:"formulas" yaml:"formulas"`
+	Init     map[string]bool   `json:"init,omitempty" yaml:"init,omitempty"`
+
+	MaxDelays    *int                `json:"maxdelays,omitempty" yaml:"maxdelays,omitempty"`
+	Comments     map[string][]string `json:"comments,omitempty" yaml:"comments,omitempty"`
+	LineComments map[string]string   `json:"line_comments,omitempty" yaml:"line_comments,omitempty"`
+}
+
+// schemeFormat определяет формат файла схемы по расширению.
+func schemeFormat(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatText
+}
+
+func (s Scheme) document() *schemeDocument {
+	formulas := make(map[string]string, len(s.Memory))
+	for _, v := range s.formulas() {
+		formulas[v] = s.source(v)
+	}
+	doc := &schemeDocument{
+		Inputs:   s.Inputs,
+		Outputs:  s.Outputs,
+		Delays:   s.delays(),
+		Formulas: formulas,
+	}
+	if len(s.Init) > 0 {
+		doc.Init = s.Init
+	}
+	if s.MaxDelays != defaultMaxDelays {
+		maxDelays := s.MaxDelays
+		doc.MaxDelays = &maxDelays
+	}
+	if len(s.Comments) > 0 {
+		doc.Comments = s.Comments
+	}
+	if len(s.LineComments) > 0 {
+		doc.LineComments = s.LineComments
+	}
+	return doc
+}
+
+// lowerNames приводит имена к нижнему регистру, как при чтении
+// текстового формата.
+func lowerNames(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	lower := make([]string, len(names))
+	for i, name := range names {
+		lower[i] = strings.ToLower(name)
+	}
+	return lower
+}
+
+func (doc *schemeDocument) scheme() (*Scheme, error) {
+	formulas := make(map[string]string, len(doc.Formulas))
+	for name, formula := range doc.Formulas {
+		formulas[strings.ToLower(name)] = strings.ToLower(formula)
+	}
+	var init map[string]bool
+	if doc.Init != nil {
+		init = make(map[string]bool, len(doc.Init))
+		for name, value := range doc.Init {
+			init[strings.ToLower(name)] = value
+		}
+	}
+	s, err := newScheme(lowerNames(doc.Inputs), lowerNames(doc.Outputs), lowerNames(doc.Delays), formulas, init)
+	if err != nil {
+		return nil, err
+	}
+	if doc.MaxDelays != nil {
+		if *doc.MaxDelays < 0 {
+			return nil, errors.New("maxdelays must be a non-negative number")
+		}
+		s.MaxDelays = *doc.MaxDelays
+	}
+	for key, comments := range doc.Comments {
+		s.Comments[strings.ToLower(key)] = comments
+	}
+	for key, comment := range doc.LineComments {
+		s.LineComments[strings.ToLower(key)] = comment
+	}
+	if err = s.check(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// encodeScheme записывает схему в заданном формате.
+func encodeScheme(s *Scheme, format string) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		// без экранирования HTML, иначе "->" записывается как "-\u003e"
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(s.document()); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	case formatYAML:
+		return yaml.Marshal(s.document())
+	}
+	return []byte(s.String()), nil
+}
+
+// decodeScheme читает схему в заданном формате; неизвестные
+// поля в JSON и YAML считаются ошибкой.
+func decodeScheme(fileName string, data []byte, format string) (*Scheme, error) {
+	doc := &schemeDocument{}
+	switch format {
+	case formatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(doc); err != nil {
+			return nil, errors.New(fileName + ": " + err.Error())
+		}
+	case formatYAML:
+		if err := yaml.UnmarshalStrict(data, doc); err != nil {
+			return nil, errors.New(fileName + ": " + err.Error())
+		}
+	default:
+		return parseScheme(fileName, bytes.NewReader(data))
+	}
+	s, err := doc.scheme()
+	if err != nil {
+		return nil, errors.New(fileName + ": " + err.Error())
+	}
+	return s, nil
+}
+
+// loadScheme читает схему из файла, формат определяется по расширению.
+func loadScheme(fileName string) (*Scheme, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return decodeScheme(fileName, data, schemeFormat(fileName))
+}
+
+// saveScheme записывает схему в файл, формат определяется по расширению.
+func saveScheme(s *Scheme, fileName string) error {
+	data, err := encodeScheme(s, schemeFormat(fileName))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/horpto/toi/lib"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestSchemeRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		s, err := createSchemeFromFile(file)
+		if err != nil {
+			t.Error(file, err)
+			continue
+		}
+		saved := s.String()
+
+		golden := strings.TrimSuffix(file, ".txt") + ".golden"
+		if *update {
+			if err := ioutil.WriteFile(golden, []byte(saved), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if saved != string(expected) {
+			t.Errorf("%s: expected\n%s\nactual:\n%s", file, expected, saved)
+		}
+
+		loaded, err := createSchemeFromFile(golden)
+		if err != nil {
+			t.Error(golden, err)
+			continue
+		}
+		if !reflect.DeepEqual(s, loaded) {
+			t.Errorf("%s: loaded scheme differs from saved one:\n%#v\n%#v", file, s, loaded)
+		}
+		if again := loaded.String(); again != saved {
+			t.Errorf("%s: second save differs:\n%s\n%s", file, saved, again)
+		}
+	}
+}
+
+func TestParseSchemeLineEndings(t *testing.T) {
+	text := "input: x\noutput: y\ny: x * z\nz: !x\n"
+	expected, err := parseScheme("lf.txt", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, eol := range []string{"\r\n", "\r"} {
+		s, err := parseScheme("eol.txt", strings.NewReader(strings.Replace(text, "\n", eol, -1)))
+		if err != nil {
+			t.Errorf("%q: %s", eol, err)
+			continue
+		}
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("%q: expected %#v, actual %#v", eol, expected, s)
+		}
+	}
+}
+
+func TestParseSchemeErrors(t *testing.T) {
+	text := "input: x\n" +
+		"output: y\n" +
+		"input: q\n" +
+		"y: x +\n" +
+		"\n" +
+		"garbage\n" +
+		"2z: x\n" +
+		"y: x\n" +
+		"maxdelays: many\n"
+	_, err := parseScheme("bad.txt", strings.NewReader(text))
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	errs, ok := err.(loadErrors)
+	if !ok {
+		t.Fatalf("expected loadErrors, actual %T: %s", err, err)
+	}
+	lines := []int{3, 4, 6, 7, 8, 9}
+	if len(errs) != len(lines) {
+		t.Fatalf("expected %d errors, actual:\n%s", len(lines), err)
+	}
+	for i, line := range lines {
+		if errs[i].line != line || errs[i].file != "bad.txt" {
+			t.Errorf("expected error at bad.txt:%d, actual %s", line, errs[i])
+		}
+	}
+
+	_, err = parseScheme("undefined.txt", strings.NewReader("input: x\noutput: y\ny: x * w\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "undefined.txt:3: ") {
+		t.Errorf("expected error for undefined identifier at line 3, actual %v", err)
+	}
+}
+
+func TestParseSchemeDeclarationErrors(t *testing.T) {
+	cases := []struct {
+		text string
+		line int
+		msg  string
+	}{
+		{"input: x\noutput: y\nmemory: x\ny: x\nx: y\n", 3, "variable 'x' is already declared at line 1"},
+		{"input: x\noutput: y, w\nmemory: z\ny: x\nz: x\n", 2, "output variable 'w' has no formula"},
+		{"input: x\noutput: y\nmemory: z, u\ny: x\nz: x\n", 3, "formula for 'u' is not defined"},
+		{"input: x\noutput: y1, y2\ny1: y2\ny2: y1 * x\n", 3, "Combinational loop: y1 -> y2 -> y1"},
+		{"input: x\noutput: y\ninit: q=1\ny: x\n", 3, "'q': initial value is given, but it is not a delay"},
+		{"input: x\noutput: y\nmemory: a, b, c\ny: a * b * c\na: x\nb: a\nc: b\n", 3, "'a, b, c': scheme has 3 delays, at most 2 allowed"},
+		{"input: x\noutput: y\ny: a * b\na: x\nb: a\nmaxdelays: 1\n", 6, "'a, b': scheme has 2 delays, at most 1 allowed"},
+	}
+	for _, c := range cases {
+		_, err := parseScheme("bad.txt", strings.NewReader(c.text))
+		errs, ok := err.(loadErrors)
+		if !ok || len(errs) != 1 {
+			t.Errorf("%q: expected one error, actual %v", c.text, err)
+			continue
+		}
+		if errs[0].line != c.line || errs[0].msg != c.msg {
+			t.Errorf("%q: expected %d: %s, actual %s", c.text, c.line, c.msg, errs[0])
+		}
+	}
+
+	text := "input: x\noutput: y, w\nmemory: z, x\ny: x\n"
+	_, err := parseScheme("bad.txt", strings.NewReader(text))
+	errs, ok := err.(loadErrors)
+	if !ok {
+		t.Fatalf("expected loadErrors, actual %T: %v", err, err)
+	}
+	lines := []int{2, 3, 3}
+	if len(errs) != len(lines) {
+		t.Fatalf("expected %d errors, actual:\n%s", len(lines), err)
+	}
+	for i, line := range lines {
+		if errs[i].line != line {
+			t.Errorf("expected error at bad.txt:%d, actual %s", line, errs[i])
+		}
+	}
+}
+
+func TestSchemeFormatsRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		s, err := createSchemeFromFile(file)
+		if err != nil {
+			t.Error(file, err)
+			continue
+		}
+		for _, format := range []string{formatJSON, formatYAML} {
+			data, err := encodeScheme(s, format)
+			if err != nil {
+				t.Error(file, format, err)
+				continue
+			}
+			loaded, err := decodeScheme(file, data, format)
+			if err != nil {
+				t.Errorf("%s %s: %s\n%s", file, format, err, data)
+				continue
+			}
+			if !reflect.DeepEqual(s, loaded) {
+				t.Errorf("%s %s: decoded scheme differs:\n%#v\n%#v", file, format, s, loaded)
+			}
+		}
+	}
+}
+
+func TestEncodeSchemeJSON(t *testing.T) {
+	text := "# sum\ninput: x # inputs\noutput: y\ny: x -> z\nz: !z\n"
+	s, err := parseScheme("comments.txt", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := encodeScheme(s, formatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &schemeDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"x -> z"`) {
+		t.Errorf("expected formula without escaping, actual:\n%s", data)
+	}
+	comments := map[string][]string{"input": {"sum"}}
+	if !reflect.DeepEqual(doc.Comments, comments) {
+		t.Errorf("expected comments %v, actual %v", comments, doc.Comments)
+	}
+	lineComments := map[string]string{"input": "inputs"}
+	if !reflect.DeepEqual(doc.LineComments, lineComments) {
+		t.Errorf("expected line comments %v, actual %v", lineComments, doc.LineComments)
+	}
+}
+
+func TestDecodeSchemeCase(t *testing.T) {
+	doc := `{"inputs": ["X"], "outputs": ["Y"], "delays": ["Z"], "formulas": {"Y": "X * Z", "Z": "!Z"}, "init": {"Z": true}}`
+	s, err := decodeScheme("upper.json", []byte(doc), formatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := parseScheme("upper.txt", strings.NewReader("INPUT: X\nOUTPUT: Y\nMEMORY: Z\nINIT: Z=1\nY: X * Z\nZ: !Z\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("expected %#v, actual %#v", expected, s)
+	}
+}
+
+func TestSchemeFormat(t *testing.T) {
+	formats := map[string]string{
+		"adder.txt":  formatText,
+		"adder":      formatText,
+		"adder.json": formatJSON,
+		"adder.YAML": formatYAML,
+		"adder.yml":  formatYAML,
+	}
+	for fileName, expected := range formats {
+		if actual := schemeFormat(fileName); actual != expected {
+			t.Errorf("%s: expected %s, actual %s", fileName, expected, actual)
+		}
+	}
+}
+
+func TestDecodeSchemeUnknownField(t *testing.T) {
+	docs := map[string]string{
+		formatJSON: `{"inputs": ["x"], "outputs": ["y"], "formulas": {"y": "x"}, "input": ["z"]}`,
+		formatYAML: "inputs: [x]\noutputs: [y]\nformulas: {y: x}\ninput: [z]\n",
+	}
+	for format, doc := range docs {
+		if _, err := decodeScheme("scheme."+format, []byte(doc), format); err == nil {
+			t.Errorf("%s: expected error for unknown field", format)
+		}
+	}
+}
+
+// stepOutputWord вычисляет выходное слово по тактам через calculate.
+func stepOutputWord(s *Scheme, signals [][]bool) ([][]bool, error) {
+	namespace := boolParser.Namespace{}
+	for _, name := range s.delays() {
+		namespace[name] = s.Init[name]
+	}
+	out := make([][]bool, len(signals))
+	for i, signal := range signals {
+		for j, in := range s.Inputs {
+			namespace[in] = signal[j]
+		}
+		res, err := s.calculate(namespace)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = make([]bool, len(s.Outputs))
+		for j, o := range s.Outputs {
+			out[i][j] = res[o]
+			delete(res, o)
+		}
+		namespace = res
+	}
+	return out, nil
+}
+
+func TestCalculateOutputWord(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, file := range files {
+		s, err := createSchemeFromFile(file)
+		if err != nil {
+			t.Error(file, err)
+			continue
+		}
+		signals := make([][]bool, 200)
+		for i := range signals {
+			signals[i] = make([]bool, len(s.Inputs))
+			for j := range signals[i] {
+				signals[i][j] = rnd.Intn(2) == 1
+			}
+		}
+		expected, err := stepOutputWord(s, signals)
+		if err != nil {
+			t.Error(file, err)
+			continue
+		}
+		actual, err := s.calculateOutputWord(signals)
+		if err != nil {
+			t.Error(file, err)
+			continue
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: expected\n%s\nactual\n%s", file, wordToString(expected), wordToString(actual))
+		}
+	}
+}
+
+func BenchmarkCalculateOutputWord(b *testing.B) {
+	s, err := createSchemeFromFile(filepath.Join("testdata", "adder.txt"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	signals := make([][]bool, 1<<16)
+	for i := range signals {
+		signals[i] = []bool{i&1 == 1, i&2 == 2}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.calculateOutputWord(signals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestValidateDelayNeverAffectsOutputs(t *testing.T) {
+	schemes := map[string]string{
+		// z и q ссылаются друг на друга, но на выход не влияют
+		"input: x\noutput: y\nmemory: z, q\ny: x + x*z\nz: q\nq: z\n": "z q",
+		// q влияет на выход через z
+		"input: x\noutput: y\nmemory: z, q\ny: x ^ z\nz: q\nq: x\n": "",
+		// y2 влияет на y1 в том же такте
+		"input: x\noutput: y1, y2\nmemory: z\ny1: y2 * x\ny2: z\nz: !z\n": "",
+	}
+	for text, expected := range schemes {
+		s, err := parseScheme("scheme.txt", strings.NewReader(text))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		never := []string{}
+		for _, d := range s.Validate() {
+			if d.Message == "delay never affects the outputs" {
+				never = append(never, d.Var)
+			}
+		}
+		if actual := strings.Join(never, " "); actual != expected {
+			t.Errorf("%q: expected warnings for %q, actual %q", text, expected, actual)
+		}
+	}
+}
+
+func TestCombinationalLoop(t *testing.T) {
+	mems := map[string]string{"y1": "y2", "y2": "y1 * z", "z": "x"}
+	_, err := newScheme([]string{"x"}, []string{"y1", "y2"}, []string{"z"}, mems, nil)
+	if err == nil || err.Error() != "Combinational loop: y1 -> y2 -> y1" {
+		t.Errorf("expected loop y1 -> y2 -> y1, actual %v", err)
+	}
+
+	// задержки в формулах - текущие значения, поэтому это не цикл
+	mems = map[string]string{"y": "x * z", "z": "y"}
+	if _, err := newScheme([]string{"x"}, []string{"y"}, []string{"z"}, mems, nil); err != nil {
+		t.Error("unexpected error for loop through a delay:", err)
+	}
+}
+
+func TestEvaluationOrder(t *testing.T) {
+	// y1 объявлен первым, но зависит от y2, а задержка z - от нового y1
+	mems := map[string]string{"y1": "!y2", "y2": "x", "z": "y1"}
+	s, err := newScheme([]string{"x"}, []string{"y1", "y2"}, []string{"z"}, mems, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order := []string{"y2", "y1", "z"}; !reflect.DeepEqual(s.order, order) {
+		t.Errorf("expected order %v, actual %v", order, s.order)
+	}
+
+	namespace := boolParser.Namespace{"x": true, "z": true}
+	res, err := s.calculate(namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := boolParser.Namespace{"y1": false, "y2": true, "z": false}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, actual %v", expected, res)
+	}
+	if _, ok := namespace["y1"]; ok {
+		t.Error("outputs must be removed from namespace after calculate")
+	}
+
+	out, err := s.calculateOutputWord([][]bool{{true}, {false}, {true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := wordToString(out); actual != strings.Join([]string{"01", "10", "01"}, " ") {
+		t.Errorf("expected 01 10 01, actual %s", actual)
+	}
+}
@@ -5,76 +5,83 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/apcera/termtables"
 	"github.com/dixonwille/wmenu"
+	"github.com/horpto/toi/lib"
 )
 
-func parseHeader(line, sep string) (string, error) {
-	parts := strings.Split(line, sep)
-
-	if len(parts) != 2 {
-		return "", errors.New("Fail to parse line:" + line)
+// wordToString записывает слово в виде "0110",
+// если буква состоит из нескольких значений - в виде "01 10".
+func wordToString(word [][]bool) string {
+	letters := make([]string, len(word))
+	separator := ""
+	for i, letter := range word {
+		for _, w := range letter {
+			letters[i] += boolToString(w)
+		}
+		if len(letter) > 1 {
+			separator = " "
+		}
 	}
-	return strings.TrimSpace(parts[1]), nil
+	return strings.Join(letters, separator)
 }
 
-func createSchemeFromFile(fileName string) (*Scheme, error) {
-	content, err := ioutil.ReadFile(fileName)
-	if err != nil {
-		return nil, err
+// parseWord разбирает слово из букв по width значений,
+// все символы, кроме 0 и 1, пропускаются.
+func parseWord(word string, width int) ([][]bool, error) {
+	bits := []bool{}
+	for _, w := range word {
+		if w == '0' || w == '1' {
+			bits = append(bits, w == '1')
+		}
+	}
+	if len(bits)%width != 0 {
+		return nil, errors.New("Длина входного слова должна быть кратна " + strconv.Itoa(width))
+	}
+	letters := make([][]bool, 0, len(bits)/width)
+	for i := 0; i < len(bits); i += width {
+		letters = append(letters, bits[i:i+width])
 	}
-	lines := strings.Split(string(content), "\r\n")
-	exprs := make(map[string]string, len(lines))
-	memory := []string{}
+	return letters, nil
+}
 
-	var in, out string
-	for i, line := range lines {
-		line = strings.ToLower(strings.TrimSpace(line))
-		switch {
-		case strings.HasPrefix(line, "#"):
-			continue
-		case strings.HasPrefix(line, "input:") && in == "":
-			in, err = parseHeader(line, ":")
-			if err != nil {
-				fmt.Errorf("miss line: %q", err.Error())
-			}
-		case strings.HasPrefix(line, "output:") && out == "":
-			out, err = parseHeader(line, ":")
-			if err != nil {
-				fmt.Errorf("miss line: %q", err.Error())
-				continue
-			}
-		case strings.HasPrefix(line, "memory:"):
-			mem, err := parseHeader(line, ":")
-			if err != nil {
-				fmt.Errorf("miss line: %q", err.Error())
-				continue
-			}
-			vars := strings.Split(mem, ",")
-			for i := range vars {
-				if v := strings.TrimSpace(vars[i]); v != "" {
-					vars[i] = v
-				}
-			}
-			memory = append(memory, vars...)
-		default:
-			parts := strings.Split(line, ":")
-			if len(parts) != 2 {
-				fmt.Errorf("cannot parse line: %s", line)
-				continue
-			}
-			exprs[parts[0]] = parts[1]
-		}
-		fmt.Printf("line %d: %s\r\n", i, line)
+// parseState разбирает начальное состояние - значения задержек подряд,
+// для пустой строки возвращает начальное состояние схемы.
+func parseState(s *Scheme, state string) (map[string]bool, error) {
+	if state == "" {
+		return s.Init, nil
+	}
+	delays := s.delays()
+	if len(state) != len(delays) || strings.Trim(state, "01") != "" {
+		return nil, errors.New("Начальное состояние должно состоять из " + strconv.Itoa(len(delays)) + " символов 0 или 1")
 	}
-	memory = append(memory, out)
-	for _, mem := range memory {
-		if _, ok := exprs[mem]; !ok {
-			return nil, errors.New("Formula for '" + mem + "' not defined")
+	init := map[string]bool{}
+	for i, name := range delays {
+		init[name] = state[i] == '1'
+	}
+	return init, nil
+}
+
+// outputWordsTable вычисляет выходные слова для всех начальных состояний.
+func outputWordsTable(s *Scheme, inputWord [][]bool) (string, error) {
+	delays := s.delays()
+	table := termtables.CreateTable()
+	table.SetModeTerminal()
+	table.AddHeaders(strings.Join(delays, ""), strings.Join(s.Outputs, ""))
+	for q := 0; q < 1<<uint(len(delays)); q++ {
+		init := boolParser.Namespace{}
+		setBits(init, delays, q)
+		outputWord, err := s.calculateOutputWordFrom(init, inputWord)
+		if err != nil {
+			return "", err
 		}
+		table.AddRow(bitsToString(q, len(delays)), wordToString(outputWord))
 	}
-	return newScheme(in, out, exprs)
+	return table.Render(), nil
 }
 
 func ask(prompt string) string {
@@ -85,30 +92,51 @@ func ask(prompt string) string {
 }
 
 func createSchemeFromStdin() (*Scheme, error) {
-	in := ask("Введите имя входного параметра(x по умолчанию):")
-	if in == "" {
-		in = "x"
+	inputs := parseList(ask("Введите через запятую имена входов(x по умолчанию):"))
+	if len(inputs) == 0 {
+		inputs = []string{"x"}
 	}
-	out := ask("Введите имя выходного параметра(y по умолчанию):")
-	if out == "" {
-		out = "y"
+	outputs := parseList(ask("Введите через запятую имена выходов(y по умолчанию):"))
+	if len(outputs) == 0 {
+		outputs = []string{"y"}
 	}
-	m := ask("Введите через запятую имена задержек:")
+	delays := parseList(ask("Введите через запятую имена задержек:"))
 	vars := map[string]string{}
-	for _, v := range strings.Split(m, ",") {
-		v = strings.TrimSpace(v)
-		if v == "" {
-			continue
-		}
+	for _, v := range delays {
 		vars[v] = ask("Введите лог.выражение для задержки '" + v + "'")
 	}
-	if _, ok := vars[out]; !ok {
-		vars[out] = ask("Введите лог.выражение для выходного параметра")
+	for _, out := range outputs {
+		if _, ok := vars[out]; !ok {
+			vars[out] = ask("Введите лог.выражение для выхода '" + out + "'")
+		}
+	}
+	init, err := parseInit(ask("Введите через запятую начальные значения задержек(например z=1,q=0, по умолчанию 0):"))
+	if err != nil {
+		return nil, err
+	}
+	s, err := newScheme(inputs, outputs, delays, vars, init)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.check(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func printWarnings(s *Scheme) {
+	for _, d := range s.Validate() {
+		if d.Warning {
+			fmt.Println(d.String())
+		}
 	}
-	return newScheme(in, out, vars)
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	var s *Scheme = nil
 
 	exited := false
@@ -122,14 +150,16 @@ func main() {
 			s1, err := createSchemeFromStdin()
 			if s1 != nil {
 				s = s1
+				printWarnings(s)
 			}
 			return err
 		})
 		menu.Option("Ввести новую схему из файла", false, func() error {
-			fileName := ask("Введите путь до файла:")
-			s1, err := createSchemeFromFile(fileName)
+			fileName := ask("Введите путь до файла (.txt, .json или .yaml):")
+			s1, err := loadScheme(fileName)
 			if s1 != nil {
 				s = s1
+				printWarnings(s)
 			}
 			return err
 		})
@@ -137,12 +167,75 @@ func main() {
 			if s == nil {
 				return errors.New("Введите сначала схему")
 			}
-			fileName := ask("Введите путь до файла:")
-			var err error
-			if fileName != "" {
-				ioutil.WriteFile(fileName, []byte(s.String()), os.ModeType)
+			fileName := ask("Введите путь до файла (.txt, .json или .yaml):")
+			if fileName == "" {
+				return nil
 			}
-			return err
+			return saveScheme(s, fileName)
+		})
+		menu.Option("Проверить схему", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			diagnostics := s.Validate()
+			for _, d := range diagnostics {
+				fmt.Println(d.String())
+			}
+			if len(diagnostics) == 0 {
+				fmt.Println("Замечаний нет")
+			}
+			return nil
+		})
+		menu.Option("Упростить формулы", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			fmt.Print(s.simplifiedFormulas())
+			return nil
+		})
+		menu.Option("Найти минимальные ДНФ", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			report, err := s.dnfReport()
+			if err != nil {
+				return err
+			}
+			fmt.Print(report)
+			return nil
+		})
+		menu.Option("Найти СДНФ, СКНФ и полиномы Жегалкина", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			report, err := s.canonicalReport()
+			if err != nil {
+				return err
+			}
+			fmt.Print(report)
+			return nil
+		})
+		menu.Option("Построить таблицу Поста", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			report, err := s.postReport()
+			if err != nil {
+				return err
+			}
+			fmt.Print(report)
+			return nil
+		})
+		menu.Option("Найти существенные и фиктивные переменные", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			report, err := s.essentialReport()
+			if err != nil {
+				return err
+			}
+			fmt.Print(report)
+			return nil
 		})
 		menu.Option("Вывести таблицу истинности", false, func() error {
 			if s == nil {
@@ -155,34 +248,93 @@ func main() {
 			fmt.Print(tt.String())
 			return nil
 		})
+		menu.Option("Вывести таблицу переходов и выходов автомата", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			m, err := s.createMealyMachine()
+			if err != nil {
+				return err
+			}
+			fmt.Print(m.String())
+			return nil
+		})
+		menu.Option("Минимизировать автомат", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			m, err := s.createMealyMachine()
+			if err != nil {
+				return err
+			}
+			fmt.Print(m.minimize().String())
+			return nil
+		})
+		menu.Option("Найти состояния, достижимые из начального", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			m, err := s.createMealyMachine()
+			if err != nil {
+				return err
+			}
+			fmt.Print(m.reachability().String())
+			return nil
+		})
+		menu.Option("Сохранить диаграмму состояний в .dot файл", false, func() error {
+			if s == nil {
+				return errors.New("Введите сначала схему")
+			}
+			m, err := s.createMealyMachine()
+			if err != nil {
+				return err
+			}
+			fileName := ask("Введите путь до файла:")
+			if fileName == "" {
+				return nil
+			}
+			if filepath.Ext(fileName) != ".dot" {
+				fileName += ".dot"
+			}
+			return ioutil.WriteFile(fileName, []byte(m.Dot()), 0644)
+		})
 		menu.Option("Найти выходное слово по входному", false, func() error {
 			if s == nil {
 				return errors.New("Введите сначала схему")
 			}
-			word := ask("Введите входное слово:")
-			inputWord := []bool{}
-			filteredWord := ""
-			for _, w := range word {
-				if w == '0' || w == '1' {
-					inputWord = append(inputWord, w == '1')
-					filteredWord += string(w)
+			prompt := "Введите входное слово:"
+			if len(s.Inputs) > 1 {
+				prompt = "Введите входное слово - значения входов " + strings.Join(s.Inputs, ",") + " подряд для каждого такта:"
+			}
+			inputWord, err := parseWord(ask(prompt), len(s.Inputs))
+			if err != nil {
+				return err
+			}
+			filteredWord := wordToString(inputWord)
+
+			delays := s.delays()
+			state := ask("Введите начальное состояние - значения задержек " + strings.Join(delays, ",") +
+				" подряд (по умолчанию из схемы, * - все состояния):")
+			if state == "*" {
+				table, err := outputWordsTable(s, inputWord)
+				if err != nil {
+					return err
 				}
+				fmt.Println(strings.Join(s.Inputs, "") + ": " + filteredWord)
+				fmt.Print(table)
+				return nil
 			}
 
-			outputWord, err := s.calculateOutputWord(inputWord)
+			init, err := parseState(s, state)
 			if err != nil {
 				return err
 			}
-			output := ""
-			for _, w := range outputWord {
-				if w {
-					output += "1"
-				} else {
-					output += "0"
-				}
+			outputWord, err := s.calculateOutputWordFrom(init, inputWord)
+			if err != nil {
+				return err
 			}
 			fmt.Println(filteredWord)
-			fmt.Println(output)
+			fmt.Println(wordToString(outputWord))
 			return nil
 		})
 		menu.Run()
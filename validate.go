@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/horpto/toi/lib"
+)
+
+// defaultMaxDelays — по условию задачи в схеме не более 2-х задержек.
+const defaultMaxDelays = 2
+
+// Diagnostic — замечание к схеме, найденное Validate.
+type Diagnostic struct {
+	Var     string // переменная, к которой относится замечание
+	Formula string // формула переменной, если она есть
+	Section string // раздел файла схемы вроде "init", если замечание о нем, а не о формуле
+	Message string
+	Warning bool // предупреждение не мешает работать со схемой
+}
+
+func (d Diagnostic) String() string {
+	buffer := ""
+	if d.Warning {
+		buffer = "warning: "
+	}
+	if d.Formula != "" {
+		return buffer + "'" + d.Var + ": " + d.Formula + "': " + d.Message
+	}
+	return buffer + "'" + d.Var + "': " + d.Message
+}
+
+// formulas возвращает имена переменных с формулой:
+// сначала выходы, затем задержки.
+func (s Scheme) formulas() []string {
+	names := []string{}
+	for _, out := range s.Outputs {
+		if _, ok := s.Memory[out]; ok {
+			names = append(names, out)
+		}
+	}
+	return append(names, s.delays()...)
+}
+
+func (s Scheme) formula(name string) string {
+	if _, ok := s.Memory[name]; ok {
+		return s.source(name)
+	}
+	return ""
+}
+
+func (s Scheme) isInput(name string) bool {
+	return contains(s.Inputs, name)
+}
+
+// Validate проверяет, что формулы ссылаются только на входы, выходы
+// и задержки, что у входов нет формул, что каждая задержка используется
+// и влияет на выходы и что задержек не больше MaxDelays (если MaxDelays > 0).
+func (s Scheme) Validate() []Diagnostic {
+	diagnostics := []Diagnostic{}
+	add := func(name string, warning bool, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Var:     name,
+			Formula: s.formula(name),
+			Message: fmt.Sprintf(format, args...),
+			Warning: warning,
+		})
+	}
+
+	for _, in := range s.Inputs {
+		if _, ok := s.Memory[in]; ok {
+			add(in, false, "input variable has formula")
+		}
+	}
+	for _, out := range s.Outputs {
+		if _, ok := s.Memory[out]; !ok {
+			add(out, false, "output variable has no formula")
+		}
+	}
+
+	used := map[string]bool{}
+	for _, name := range s.formulas() {
+		for _, v := range boolParser.Variables(s.Memory[name]) {
+			if _, ok := s.Memory[v]; !ok && !s.isInput(v) {
+				add(name, false, "identifier '%s' is not defined", v)
+			}
+			if v != name {
+				used[v] = true
+			}
+		}
+	}
+
+	delays := s.delays()
+	for _, delay := range delays {
+		if !used[delay] {
+			add(delay, true, "delay is not used by other formulas")
+		}
+	}
+	if s.MaxDelays > 0 && len(delays) > s.MaxDelays {
+		diagnostics = append(diagnostics, Diagnostic{
+			Var:     strings.Join(delays, ", "),
+			Section: "memory",
+			Message: fmt.Sprintf("scheme has %d delays, at most %d allowed", len(delays), s.MaxDelays),
+		})
+	}
+
+	isDelay := map[string]bool{}
+	for _, delay := range delays {
+		isDelay[delay] = true
+	}
+	names := make([]string, 0, len(s.Init))
+	for k := range s.Init {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if !isDelay[k] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Var:     k,
+				Formula: s.formula(k),
+				Section: "init",
+				Message: "initial value is given, but it is not a delay",
+			})
+		}
+	}
+
+	// существенность ищется по таблице истинности, поэтому только
+	// для схемы без ошибок и не слишком большой
+	for _, d := range diagnostics {
+		if !d.Warning {
+			return diagnostics
+		}
+	}
+	if len(s.Inputs)+len(delays) > maxInfluenceVars {
+		return diagnostics
+	}
+	influencing, err := s.influence()
+	if err != nil {
+		return diagnostics
+	}
+	for _, delay := range delays {
+		if used[delay] && !influencing[delay] {
+			add(delay, true, "delay never affects the outputs")
+		}
+	}
+	return diagnostics
+}
+
+// check возвращает ошибки, найденные Validate, одной ошибкой.
+func (s Scheme) check() error {
+	msgs := []string{}
+	for _, d := range s.Validate() {
+		if !d.Warning {
+			msgs = append(msgs, d.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
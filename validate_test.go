@@ -0,0 +1,134 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testsValidate = []struct {
+	name      string
+	inputs    []string
+	outputs   []string
+	mems      map[string]string
+	init      map[string]bool
+	maxDelays int // -1 - по умолчанию
+	expected  []Diagnostic
+}{
+	{
+		name:      "valid",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "x * z", "z": "x"},
+		maxDelays: -1,
+		expected:  []Diagnostic{},
+	},
+	{
+		name:      "input with formula",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "x", "x": "y"},
+		maxDelays: -1,
+		expected: []Diagnostic{
+			{Var: "x", Formula: "y", Message: "input variable has formula"},
+		},
+	},
+	{
+		name:      "unused delay",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "x", "z": "!z"},
+		maxDelays: -1,
+		expected: []Diagnostic{
+			{Var: "z", Formula: "!z", Message: "delay is not used by other formulas", Warning: true},
+		},
+	},
+	{
+		name:      "too many delays",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "a * b * c", "a": "x", "b": "a", "c": "b"},
+		maxDelays: -1,
+		expected: []Diagnostic{
+			{Var: "a, b, c", Section: "memory", Message: "scheme has 3 delays, at most 2 allowed"},
+		},
+	},
+	{
+		name:      "maxdelays raised",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "a * b * c", "a": "x", "b": "a", "c": "b"},
+		maxDelays: 3,
+		expected:  []Diagnostic{},
+	},
+	{
+		name:      "maxdelays lowered",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "a", "a": "x"},
+		maxDelays: 0,
+		expected:  []Diagnostic{},
+	},
+	{
+		name:      "maxdelays 1",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "a * b", "a": "x", "b": "a"},
+		maxDelays: 1,
+		expected: []Diagnostic{
+			{Var: "a, b", Section: "memory", Message: "scheme has 2 delays, at most 1 allowed"},
+		},
+	},
+	{
+		name:      "init of non-delays",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "x * z", "z": "x"},
+		init:      map[string]bool{"x": true, "y": false, "z": true},
+		maxDelays: -1,
+		expected: []Diagnostic{
+			{Var: "x", Section: "init", Message: "initial value is given, but it is not a delay"},
+			{Var: "y", Formula: "x * z", Section: "init", Message: "initial value is given, but it is not a delay"},
+		},
+	},
+	{
+		name:      "undefined identifier",
+		inputs:    []string{"x"},
+		outputs:   []string{"y"},
+		mems:      map[string]string{"y": "x + w"},
+		maxDelays: -1,
+		expected: []Diagnostic{
+			{Var: "y", Formula: "x + w", Message: "identifier 'w' is not defined"},
+		},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range testsValidate {
+		s, err := newScheme(test.inputs, test.outputs, nil, test.mems, test.init)
+		if err != nil {
+			t.Error(test.name, err)
+			continue
+		}
+		if test.maxDelays >= 0 {
+			s.MaxDelays = test.maxDelays
+		}
+		if actual := s.Validate(); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %v, actual %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestValidateMaxDelaysFromFile(t *testing.T) {
+	text := "input: x\noutput: y\nmemory: a, b, c\ny: a * b * c\na: x\nb: a\nc: b\n"
+	if _, err := parseScheme("scheme.txt", strings.NewReader(text)); err == nil {
+		t.Error("expected error for 3 delays by default")
+	}
+	s, err := parseScheme("scheme.txt", strings.NewReader("maxdelays: 3\n"+text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MaxDelays != 3 || len(s.Validate()) != 0 {
+		t.Errorf("expected maxdelays 3 and no diagnostics, actual %d and %v", s.MaxDelays, s.Validate())
+	}
+}